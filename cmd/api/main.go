@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,12 +11,18 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // часовые пояса для статистики в образе без tzdata
 
 	"github.com/gin-gonic/gin"
 	swaggerDocs "github.com/raison-collab/LinkShorternetBackend/docs" // импорт swagger документации https://github.com/swaggo/swag
 	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/router"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/clickqueue"
 	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/config"
 	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/database"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/repository"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/rollup"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/sweeper"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
 	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
 )
 
@@ -64,8 +72,83 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Start click ingestion pipeline
+	var clickQueue usecase.ClickQueue
+	var clickPipeline *clickqueue.Pipeline
+	if cfg.Clicks.Async {
+		hostname, _ := os.Hostname()
+		clickPipeline = clickqueue.NewPipeline(
+			repository.NewLinkClickRepository(db),
+			redisClient,
+			clickqueue.Config{
+				QueueSize:     cfg.Clicks.QueueSize,
+				Workers:       cfg.Clicks.Workers,
+				BatchSize:     cfg.Clicks.BatchSize,
+				FlushInterval: time.Duration(cfg.Clicks.FlushIntervalMs) * time.Millisecond,
+				Stream:        cfg.Clicks.RedisStream,
+				StreamMaxLen:  cfg.Clicks.StreamMaxLen,
+				ClaimIdle:     time.Duration(cfg.Clicks.StreamClaimIdleSeconds) * time.Second,
+				ConsumerGroup: cfg.App.Name,
+				ConsumerName:  hostname,
+			},
+			log,
+		)
+		if err := clickPipeline.Start(context.Background()); err != nil {
+			log.Fatalf("Failed to start click pipeline: %v", err)
+		}
+		clickQueue = clickPipeline
+	}
+
+	// Start click rollup aggregator
+	var clickAggregator *rollup.Aggregator
+	if cfg.Rollup.Enabled {
+		clickAggregator = rollup.NewAggregator(
+			repository.NewClickRollupRepository(db),
+			rollup.Config{
+				Interval:       time.Duration(cfg.Rollup.IntervalSeconds) * time.Second,
+				Grace:          time.Duration(cfg.Rollup.GraceMinutes) * time.Minute,
+				MaxHoursPerRun: cfg.Rollup.MaxHoursPerRun,
+			},
+			log,
+		)
+		clickAggregator.Start()
+	}
+
+	// Start expired and deleted links sweeper
+	var linkSweeper *sweeper.Sweeper
+	if cfg.Sweeper.Enabled {
+		linkRepo := repository.NewLinkRepository(db)
+		if cfg.Cache.Enabled {
+			// Purged codes must leave the redirect cache too
+			linkRepo = repository.NewCachedLinkRepository(
+				linkRepo,
+				redisClient,
+				time.Duration(cfg.Cache.LinkTTLMinutes)*time.Minute,
+				time.Duration(cfg.Cache.NegativeTTLSeconds)*time.Second,
+			)
+		}
+
+		interval := time.Duration(cfg.Sweeper.IntervalSeconds) * time.Second
+		// The lock outlives a few missed runs, so a crashed leader is replaced quickly
+		locker := sweeper.NewRedisLocker(redisClient, cfg.App.Name+":link_sweeper", lockOwner(), 3*interval)
+
+		linkSweeper = sweeper.NewSweeper(
+			linkRepo,
+			locker,
+			sweeper.Config{
+				Interval:       interval,
+				Grace:          time.Duration(cfg.Sweeper.GraceHours) * time.Hour,
+				BatchSize:      cfg.Sweeper.BatchSize,
+				Archive:        cfg.Sweeper.Mode != "delete",
+				TrashRetention: time.Duration(cfg.Sweeper.TrashDays) * 24 * time.Hour,
+			},
+			log,
+		)
+		linkSweeper.Start()
+	}
+
 	// Initialize router
-	r := router.NewRouter(db, redisClient, cfg, log)
+	r := router.NewRouter(db, redisClient, clickQueue, cfg, log)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -96,8 +179,35 @@ func main() {
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.Errorf("Server forced to shutdown: %v", err)
+	}
+
+	// Flush clicks accepted before the server stopped
+	if clickPipeline != nil {
+		if err := clickPipeline.Shutdown(ctx); err != nil {
+			log.Errorf("Failed to drain click pipeline: %v", err)
+		}
+	}
+
+	if clickAggregator != nil {
+		if err := clickAggregator.Shutdown(ctx); err != nil {
+			log.Errorf("Failed to stop click aggregator: %v", err)
+		}
+	}
+
+	if linkSweeper != nil {
+		if err := linkSweeper.Shutdown(ctx); err != nil {
+			log.Errorf("Failed to stop link sweeper: %v", err)
+		}
 	}
 
 	log.Info("Server exited")
 }
+
+// lockOwner identifies this process in distributed locks: several containers may share a hostname
+func lockOwner() string {
+	hostname, _ := os.Hostname()
+	token := make([]byte, 8)
+	_, _ = rand.Read(token)
+	return hostname + "-" + hex.EncodeToString(token)
+}
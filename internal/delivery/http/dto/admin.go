@@ -0,0 +1,39 @@
+package dto
+
+// AdminUserListRequest представляет параметры поиска пользователей
+type AdminUserListRequest struct {
+	PaginationRequest
+	Search string `form:"search"`
+	Role   string `form:"role" binding:"omitempty,oneof=user admin"`
+}
+
+// AdminLinkListRequest представляет параметры поиска ссылок всех пользователей
+type AdminLinkListRequest struct {
+	PaginationRequest
+	Search   string `form:"search"`
+	UserID   *int64 `form:"user_id"`
+	IsActive *bool  `form:"is_active"`
+}
+
+// SetRoleRequest представляет запрос на смену роли пользователя
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin" example:"admin"`
+}
+
+// UserListResponse представляет страницу пользователей
+type UserListResponse struct {
+	Items []*UserResponse `json:"items"`
+	Total int64           `json:"total"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+}
+
+// LinkListResponse представляет страницу ссылок
+type LinkListResponse struct {
+	Items    []*LinkResponse `json:"items"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	Limit    int             `json:"limit"`
+	HasNext  bool            `json:"has_next"`
+	NextPage *int            `json:"next_page,omitempty"` // отсутствует на последней странице
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "encoding/json"
+
 // ErrorResponse представляет стандартный ответ об ошибке
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -16,3 +18,37 @@ type PaginationRequest struct {
 func (p *PaginationRequest) GetOffset() int {
 	return (p.Page - 1) * p.Limit
 }
+
+// NextPage возвращает номер следующей страницы или nil, если текущая последняя
+func (p *PaginationRequest) NextPage(total int64) *int {
+	if int64(p.Page)*int64(p.Limit) >= total {
+		return nil
+	}
+	next := p.Page + 1
+	return &next
+}
+
+// Optional различает отсутствующее в JSON поле и явно переданный null
+type Optional[T any] struct {
+	Set   bool // поле присутствовало в запросе
+	Null  bool // поле передано как null
+	Value T
+}
+
+// UnmarshalJSON вызывается только для присутствующих полей, в том числе для null
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Ptr возвращает указатель на значение, если оно передано и не равно null
+func (o Optional[T]) Ptr() *T {
+	if !o.Set || o.Null {
+		return nil
+	}
+	return &o.Value
+}
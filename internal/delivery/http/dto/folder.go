@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+)
+
+// FolderRequest представляет запрос на создание или переименование папки
+type FolderRequest struct {
+	Name string `json:"name" binding:"required" example:"Marketing"`
+}
+
+// FolderResponse представляет папку
+type FolderResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MoveLinkRequest представляет перемещение ссылки в папку; null убирает ссылку из папки
+type MoveLinkRequest struct {
+	FolderID *int64 `json:"folder_id" example:"3"`
+}
+
+// TagRequest представляет запрос на создание или переименование тега
+type TagRequest struct {
+	Name string `json:"name" binding:"required" example:"promo"`
+}
+
+// TagResponse представляет тег
+type TagResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetLinkTagsRequest представляет новый набор тегов ссылки; пустой список снимает все теги
+type SetLinkTagsRequest struct {
+	Tags []string `json:"tags" binding:"required" example:"promo,spring"`
+}
+
+// FolderFromEntity преобразует entity в DTO
+func FolderFromEntity(folder *entity.Folder) *FolderResponse {
+	return &FolderResponse{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+	}
+}
+
+// TagFromEntity преобразует entity в DTO
+func TagFromEntity(tag *entity.Tag) *TagResponse {
+	return &TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+	}
+}
@@ -10,35 +10,195 @@ import (
 type CreateLinkRequest struct {
 	URL        string     `json:"url" binding:"required,url"`
 	CustomCode string     `json:"custom_code,omitempty"`
+	Title      string     `json:"title,omitempty" binding:"max=255" example:"Spring sale landing"`
 	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2025-12-31T23:59:59Z"`
+	StartsAt   *time.Time `json:"starts_at,omitempty" example:"2025-06-01T09:00:00Z"` // до этого момента ссылка не открывается
+	UTM        *UTMParams `json:"utm,omitempty"`
+	Password   string     `json:"password,omitempty" example:"s3cret"` // пароль для перехода по ссылке
+	MaxClicks  *int64     `json:"max_clicks,omitempty" binding:"omitempty,min=1" example:"1"`
+	// DeleteWhenExhausted удаляет ссылку после перехода, исчерпавшего max_clicks
+	DeleteWhenExhausted bool `json:"delete_when_exhausted,omitempty" example:"true"`
+	// FallbackURL - куда вести посетителей после истечения, отключения или исчерпания ссылки
+	FallbackURL string `json:"fallback_url,omitempty" example:"https://example.com/sale-ended"`
 }
 
-// UpdateLinkRequest представляет запрос на обновление ссылки
+// LinkListRequest представляет параметры списка ссылок пользователя.
+// Даты created_from и created_to передаются в RFC 3339
+type LinkListRequest struct {
+	PaginationRequest
+	Search      string     `form:"search"`
+	IsActive    *bool      `form:"is_active"`
+	Expired     *bool      `form:"expired"`
+	CreatedFrom *time.Time `form:"created_from"`
+	CreatedTo   *time.Time `form:"created_to"`
+	MinClicks   *int64     `form:"min_clicks" binding:"omitempty,min=0"`
+	Tag         string     `form:"tag"`
+	Folder      *int64     `form:"folder"`
+	Sort        string     `form:"sort,default=created" binding:"oneof=created updated clicks"`
+	Order       string     `form:"order,default=desc" binding:"oneof=asc desc"`
+}
+
+// LinkTrashRequest представляет параметры списка удаленных ссылок пользователя
+type LinkTrashRequest struct {
+	PaginationRequest
+	Search string `form:"search"`
+}
+
+// UTMParams представляет параметры UTM ссылки. При создании они дописываются к url
+// и заменяют одноименные параметры, уже указанные в нем
+type UTMParams struct {
+	Source   string `json:"source,omitempty" binding:"max=255" example:"newsletter"`
+	Medium   string `json:"medium,omitempty" binding:"max=255" example:"email"`
+	Campaign string `json:"campaign,omitempty" binding:"max=255" example:"spring_sale"`
+	Term     string `json:"term,omitempty" binding:"max=255"`
+	Content  string `json:"content,omitempty" binding:"max=255"`
+}
+
+// BatchCreateLinksRequest представляет запрос на пакетное создание ссылок.
+// Элементы проверяются по отдельности, чтобы вернуть ошибку для каждого
+type BatchCreateLinksRequest struct {
+	Links []CreateLinkRequest `json:"links" binding:"required,min=1,max=500"`
+	Mode  string              `json:"mode,omitempty" binding:"omitempty,oneof=atomic partial" example:"atomic"`
+}
+
+// BatchLinkResultResponse представляет результат создания одного элемента пакета
+type BatchLinkResultResponse struct {
+	Index  int           `json:"index"`
+	Status string        `json:"status" example:"created"`
+	Link   *LinkResponse `json:"link,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// BatchCreateLinksResponse представляет итог пакетного создания ссылок
+type BatchCreateLinksResponse struct {
+	Mode    string                    `json:"mode"`
+	Created int                       `json:"created"`
+	Failed  int                       `json:"failed"`
+	Results []BatchLinkResultResponse `json:"results"`
+}
+
+// ImportRowError описывает ошибку в строке импортируемого CSV
+type ImportRowError struct {
+	Row   int    `json:"row" example:"3"`
+	Error string `json:"error" example:"invalid URL"`
+}
+
+// ImportLinksResponse представляет итог импорта ссылок
+type ImportLinksResponse struct {
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// UpdateLinkRequest представляет запрос на обновление ссылки (PUT).
+// Отсутствующие поля и пустые url и custom_code не меняются; пустые title, password и fallback_url
+// снимают название, пароль и резервный адрес. Срок действия и время открытия снимаются через PATCH со значением null
 type UpdateLinkRequest struct {
-	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-12-31T23:59:59Z"`
+	URL         string     `json:"url,omitempty" binding:"omitempty,url"`
+	CustomCode  string     `json:"custom_code,omitempty"`
+	Title       *string    `json:"title,omitempty" binding:"omitempty,max=255"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2025-12-31T23:59:59Z"`
+	StartsAt    *time.Time `json:"starts_at,omitempty" example:"2025-06-01T09:00:00Z"`
+	IsActive    *bool      `json:"is_active,omitempty" example:"true"`
+	Password    *string    `json:"password,omitempty" example:"s3cret"`
+	FallbackURL *string    `json:"fallback_url,omitempty" example:"https://example.com/sale-ended"`
+}
+
+// PatchLinkRequest представляет частичное обновление ссылки (PATCH).
+// Отсутствующие поля не меняются, expires_at: null снимает срок действия, starts_at: null - время открытия,
+// title: null - название, password: null - пароль, fallback_url: null - резервный адрес
+type PatchLinkRequest struct {
+	URL         Optional[string]    `json:"url" swaggertype:"string" example:"https://example.com/new"`
+	CustomCode  Optional[string]    `json:"custom_code" swaggertype:"string" example:"promo"`
+	Title       Optional[string]    `json:"title" swaggertype:"string" example:"Spring sale landing"`
+	ExpiresAt   Optional[time.Time] `json:"expires_at" swaggertype:"string" example:"2025-12-31T23:59:59Z"`
+	StartsAt    Optional[time.Time] `json:"starts_at" swaggertype:"string" example:"2025-06-01T09:00:00Z"`
+	IsActive    Optional[bool]      `json:"is_active" swaggertype:"boolean" example:"true"`
+	Password    Optional[string]    `json:"password" swaggertype:"string" example:"s3cret"`
+	FallbackURL Optional[string]    `json:"fallback_url" swaggertype:"string" example:"https://example.com/sale-ended"`
 }
 
 // LinkResponse представляет ответ с данными ссылки
 type LinkResponse struct {
-	ID          int64      `json:"id"`
-	ShortCode   string     `json:"short_code"`
-	ShortURL    string     `json:"short_url"`
-	OriginalURL string     `json:"original_url"`
-	Clicks      int64      `json:"clicks"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          int64  `json:"id"`
+	ShortCode   string `json:"short_code"`
+	ShortURL    string `json:"short_url"`
+	OriginalURL string `json:"original_url"`
+	Title       string `json:"title,omitempty"`
+	UserID      *int64 `json:"user_id,omitempty"`
+	Clicks      int64  `json:"clicks"`
+	IsActive    bool   `json:"is_active"`
+	// DisabledByAdmin - ссылка отключена администратором; владелец не может ее включить
+	DisabledByAdmin bool       `json:"disabled_by_admin"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	StartsAt        *time.Time `json:"starts_at,omitempty"`
+	UTM             *UTMParams `json:"utm,omitempty"`
+	FolderID        *int64     `json:"folder_id,omitempty"`
+	Tags            []string   `json:"tags"`
+	HasPassword     bool       `json:"password_protected"`
+	MaxClicks       *int64     `json:"max_clicks,omitempty"`
+	// RemainingClicks - сколько переходов осталось до исчерпания max_clicks
+	RemainingClicks     *int64    `json:"remaining_clicks,omitempty"`
+	DeleteWhenExhausted bool      `json:"delete_when_exhausted,omitempty"`
+	FallbackURL         string    `json:"fallback_url,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	// DeletedAt заполнен только у ссылок из корзины
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // LinkStatsResponse представляет статистику по ссылке
 type LinkStatsResponse struct {
-	LinkID          int64                  `json:"link_id"`
-	TotalClicks     int64                  `json:"total_clicks"`
-	UniqueClicks    int64                  `json:"unique_clicks"`
-	ClicksByDate    map[string]int64       `json:"clicks_by_date"`
-	ClicksByCountry map[string]int64       `json:"clicks_by_country"`
-	ClicksByDevice  map[string]int64       `json:"clicks_by_device"`
-	TopReferers     []RefererStatsResponse `json:"top_referers"`
+	LinkID            int64                  `json:"link_id"`
+	TotalClicks       int64                  `json:"total_clicks"`
+	UniqueClicks      int64                  `json:"unique_clicks"`
+	UniqueClicksSince *time.Time             `json:"unique_clicks_since,omitempty"` // начало подсчета уникальных, если период длиннее 90 дней
+	BotClicks         int64                  `json:"bot_clicks"`
+	ClicksByDate      map[string]int64       `json:"clicks_by_date"` // устарело, используйте timeline
+	Granularity       string                 `json:"granularity" example:"day"`
+	Timezone          string                 `json:"timezone" example:"Europe/Moscow"`
+	Timeline          []TimeBucketResponse   `json:"timeline"`
+	ClicksByCountry   map[string]int64       `json:"clicks_by_country"`
+	ClicksByDevice    map[string]int64       `json:"clicks_by_device"`
+	ClicksByOS        map[string]int64       `json:"clicks_by_os"`
+	ClicksByBrowser   map[string]int64       `json:"clicks_by_browser"`
+	ClicksByCampaign  map[string]int64       `json:"clicks_by_campaign"`
+	TopReferers       []RefererStatsResponse `json:"top_referers"`
+}
+
+// TimeBucketResponse представляет количество кликов за один интервал временного ряда
+type TimeBucketResponse struct {
+	Start  time.Time `json:"start"`
+	Clicks int64     `json:"clicks"`
+}
+
+// ClickListRequest представляет параметры журнала кликов
+type ClickListRequest struct {
+	From   *time.Time `form:"from"`
+	To     *time.Time `form:"to"`
+	Cursor string     `form:"cursor"`
+	Limit  int        `form:"limit,default=50" binding:"min=1,max=500"`
+}
+
+// ClickResponse представляет отдельный клик
+type ClickResponse struct {
+	ID        int64     `json:"id"`
+	ClickedAt time.Time `json:"clicked_at"`
+	Referer   string    `json:"referer,omitempty"`
+	Country   string    `json:"country,omitempty"`
+	City      string    `json:"city,omitempty"`
+	Device    string    `json:"device"`
+	OS        string    `json:"os,omitempty"`
+	Browser   string    `json:"browser,omitempty"`
+	IsBot     bool      `json:"is_bot"`
+	IPHash    string    `json:"ip_hash"`
+}
+
+// ClickListResponse представляет страницу журнала кликов
+type ClickListResponse struct {
+	Items      []*ClickResponse `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 // RefererStatsResponse представляет статистику по источникам переходов
@@ -49,15 +209,51 @@ type RefererStatsResponse struct {
 
 // LinkFromEntity преобразует entity в DTO
 func LinkFromEntity(link *entity.Link, baseURL string) *LinkResponse {
+	var utm *UTMParams
+	if !link.UTM.IsZero() {
+		utm = &UTMParams{
+			Source:   link.UTM.Source,
+			Medium:   link.UTM.Medium,
+			Campaign: link.UTM.Campaign,
+			Term:     link.UTM.Term,
+			Content:  link.UTM.Content,
+		}
+	}
+
+	tags := link.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	var remaining *int64
+	if link.MaxClicks != nil {
+		left := max(*link.MaxClicks-link.Uses, 0)
+		remaining = &left
+	}
+
 	return &LinkResponse{
-		ID:          link.ID,
-		ShortCode:   link.ShortCode,
-		ShortURL:    baseURL + "/" + link.ShortCode,
-		OriginalURL: link.OriginalURL,
-		Clicks:      link.Clicks,
-		ExpiresAt:   link.ExpiresAt,
-		CreatedAt:   link.CreatedAt,
-		UpdatedAt:   link.UpdatedAt,
+		ID:                  link.ID,
+		ShortCode:           link.ShortCode,
+		ShortURL:            baseURL + "/" + link.ShortCode,
+		OriginalURL:         link.OriginalURL,
+		Title:               link.Title,
+		UserID:              link.UserID,
+		Clicks:              link.Clicks,
+		IsActive:            link.IsActive,
+		DisabledByAdmin:     link.DisabledByAdmin,
+		ExpiresAt:           link.ExpiresAt,
+		StartsAt:            link.StartsAt,
+		UTM:                 utm,
+		FolderID:            link.FolderID,
+		Tags:                tags,
+		HasPassword:         link.IsPasswordProtected(),
+		MaxClicks:           link.MaxClicks,
+		RemainingClicks:     remaining,
+		DeleteWhenExhausted: link.DeleteWhenExhausted,
+		FallbackURL:         link.FallbackURL,
+		CreatedAt:           link.CreatedAt,
+		UpdatedAt:           link.UpdatedAt,
+		DeletedAt:           link.DeletedAt,
 	}
 }
 
@@ -71,13 +267,45 @@ func LinkStatsFromEntity(stats *entity.LinkStats) *LinkStatsResponse {
 		}
 	}
 
+	timeline := make([]TimeBucketResponse, len(stats.Timeline))
+	for i, bucket := range stats.Timeline {
+		timeline[i] = TimeBucketResponse{
+			Start:  bucket.Start,
+			Clicks: bucket.Clicks,
+		}
+	}
+
 	return &LinkStatsResponse{
-		LinkID:          stats.LinkID,
-		TotalClicks:     stats.TotalClicks,
-		UniqueClicks:    stats.UniqueClicks,
-		ClicksByDate:    stats.ClicksByDate,
-		ClicksByCountry: stats.ClicksByCountry,
-		ClicksByDevice:  stats.ClicksByDevice,
-		TopReferers:     referers,
+		LinkID:            stats.LinkID,
+		TotalClicks:       stats.TotalClicks,
+		UniqueClicks:      stats.UniqueClicks,
+		UniqueClicksSince: stats.UniqueClicksSince,
+		BotClicks:         stats.BotClicks,
+		ClicksByDate:      stats.ClicksByDate,
+		Granularity:       string(stats.Granularity),
+		Timezone:          stats.Timezone,
+		Timeline:          timeline,
+		ClicksByCountry:   stats.ClicksByCountry,
+		ClicksByDevice:    stats.ClicksByDevice,
+		ClicksByOS:        stats.ClicksByOS,
+		ClicksByBrowser:   stats.ClicksByBrowser,
+		ClicksByCampaign:  stats.ClicksByCampaign,
+		TopReferers:       referers,
+	}
+}
+
+// ClickFromEntity преобразует клик в DTO. IPAddress должен уже содержать хеш
+func ClickFromEntity(click *entity.LinkClick) *ClickResponse {
+	return &ClickResponse{
+		ID:        click.ID,
+		ClickedAt: click.ClickedAt,
+		Referer:   click.Referer,
+		Country:   click.Country,
+		City:      click.City,
+		Device:    click.DeviceType,
+		OS:        click.OS,
+		Browser:   click.Browser,
+		IsBot:     click.IsBot,
+		IPHash:    click.IPAddress,
 	}
 }
@@ -8,15 +8,19 @@ import (
 
 // UserResponse представляет ответ с данными пользователя
 type UserResponse struct {
-	ID        int64     `json:"id"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          int64     `json:"id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	FallbackURL string    `json:"fallback_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 // UserUpdateRequest представляет запрос на обновление пользователя
 type UserUpdateRequest struct {
 	Email string `json:"email,omitempty" binding:"omitempty,email"`
+	// FallbackURL - куда вести посетителей ссылок без собственного fallback_url; пустая строка убирает его
+	FallbackURL *string `json:"fallback_url,omitempty" example:"https://example.com/gone"`
 }
 
 // PasswordChangeRequest представляет запрос на смену пароля
@@ -28,9 +32,116 @@ type PasswordChangeRequest struct {
 // UserFromEntity преобразует entity в DTO
 func UserFromEntity(user *entity.User) *UserResponse {
 	return &UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:          user.ID,
+		Email:       user.Email,
+		Role:        string(user.Role),
+		FallbackURL: user.FallbackURL,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
+
+// CreateAPIKeyRequest представляет запрос на выпуск API-ключа
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100" example:"CI pipeline"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=links:read links:write stats:read" example:"links:write"`
+}
+
+// APIKeyResponse представляет API-ключ без секрета
+type APIKeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse представляет только что выпущенный ключ; секрет показывается один раз
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// APIKeyFromEntity преобразует entity в DTO
+func APIKeyFromEntity(key *entity.APIKey) *APIKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// PeriodComparisonResponse представляет значение за период и за предыдущий период той же длины
+type PeriodComparisonResponse struct {
+	Current       int64    `json:"current"`
+	Previous      int64    `json:"previous"`
+	GrowthPercent *float64 `json:"growth_percent" example:"12.5"` // null, если в предыдущем периоде кликов не было
+}
+
+// TopLinkResponse представляет ссылку и ее клики за период
+type TopLinkResponse struct {
+	Link   *LinkResponse `json:"link"`
+	Clicks int64         `json:"clicks"`
+}
+
+// DashboardResponse представляет аналитику по всем ссылкам пользователя
+type DashboardResponse struct {
+	From         time.Time                `json:"from"`
+	To           time.Time                `json:"to"`
+	Granularity  string                   `json:"granularity" example:"day"`
+	Timezone     string                   `json:"timezone" example:"Europe/Moscow"`
+	TotalLinks   int64                    `json:"total_links"`
+	ActiveLinks  int64                    `json:"active_links"`
+	Clicks       PeriodComparisonResponse `json:"clicks"`
+	UniqueClicks PeriodComparisonResponse `json:"unique_clicks"`
+	Timeline     []TimeBucketResponse     `json:"timeline"`
+	TopLinks     []TopLinkResponse        `json:"top_links"`
+	TopCampaigns map[string]int64         `json:"top_campaigns"`
+	TopCountries map[string]int64         `json:"top_countries"`
+	TopReferers  []RefererStatsResponse   `json:"top_referers"`
+}
+
+// DashboardFromEntity преобразует аналитику аккаунта в DTO
+func DashboardFromEntity(d *entity.Dashboard, baseURL string) *DashboardResponse {
+	timeline := make([]TimeBucketResponse, len(d.Timeline))
+	for i, bucket := range d.Timeline {
+		timeline[i] = TimeBucketResponse{Start: bucket.Start, Clicks: bucket.Clicks}
+	}
+
+	topLinks := make([]TopLinkResponse, len(d.TopLinks))
+	for i, item := range d.TopLinks {
+		topLinks[i] = TopLinkResponse{Link: LinkFromEntity(item.Link, baseURL), Clicks: item.Clicks}
+	}
+
+	referers := make([]RefererStatsResponse, len(d.TopReferers))
+	for i, ref := range d.TopReferers {
+		referers[i] = RefererStatsResponse{Referer: ref.Referer, Count: ref.Count}
+	}
+
+	return &DashboardResponse{
+		From:         d.From,
+		To:           d.To,
+		Granularity:  string(d.Granularity),
+		Timezone:     d.Timezone,
+		TotalLinks:   d.TotalLinks,
+		ActiveLinks:  d.ActiveLinks,
+		Clicks:       PeriodComparisonResponse(d.Clicks),
+		UniqueClicks: PeriodComparisonResponse(d.UniqueClicks),
+		Timeline:     timeline,
+		TopLinks:     topLinks,
+		TopCampaigns: d.TopCampaigns,
+		TopCountries: d.TopCountries,
+		TopReferers:  referers,
 	}
 }
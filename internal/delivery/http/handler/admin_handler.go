@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"errors"
+	"expvar"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/config"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+type adminHandler struct {
+	adminUC usecase.AdminUseCase
+	log     logger.Logger
+	cfg     *config.Config
+}
+
+// NewAdminHandler создает новый handler для административного API
+func NewAdminHandler(adminUC usecase.AdminUseCase, log logger.Logger, cfg *config.Config) *adminHandler {
+	return &adminHandler{
+		adminUC: adminUC,
+		log:     log,
+		cfg:     cfg,
+	}
+}
+
+// ListUsers godoc
+// @Summary Список пользователей
+// @Description Возвращает всех пользователей с поиском по email и фильтром по роли
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param search query string false "Подстрока email"
+// @Param role query string false "Роль (user, admin)"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Success 200 {object} dto.UserListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /admin/users [get]
+func (h *adminHandler) ListUsers(c *gin.Context) {
+	var req dto.AdminUserListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Error("Некорректные параметры запроса:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректные параметры запроса",
+		})
+		return
+	}
+
+	users, total, err := h.adminUC.ListUsers(c.Request.Context(), entity.UserFilter{
+		Search: req.Search,
+		Role:   entity.UserRole(req.Role),
+		Offset: req.GetOffset(),
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		h.log.Error("Ошибка получения списка пользователей:", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "Внутренняя ошибка сервера",
+		})
+		return
+	}
+
+	items := make([]*dto.UserResponse, len(users))
+	for i, user := range users {
+		items[i] = dto.UserFromEntity(user)
+	}
+
+	c.JSON(http.StatusOK, dto.UserListResponse{
+		Items: items,
+		Total: total,
+		Page:  req.Page,
+		Limit: req.Limit,
+	})
+}
+
+// SetUserRole godoc
+// @Summary Смена роли пользователя
+// @Description Назначает пользователю роль user или admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param request body dto.SetRoleRequest true "Новая роль"
+// @Success 200 {object} dto.UserResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /admin/users/{id}/role [put]
+func (h *adminHandler) SetUserRole(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректный ID пользователя",
+		})
+		return
+	}
+
+	var req dto.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Ошибка привязки запроса:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректный формат запроса",
+		})
+		return
+	}
+
+	user, err := h.adminUC.SetUserRole(c.Request.Context(), userID, entity.UserRole(req.Role))
+	if err != nil {
+		h.log.Error("Ошибка смены роли пользователя:", err)
+
+		switch err {
+		case usecase.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: "Пользователь не найден",
+			})
+		case usecase.ErrInvalidRole:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: "Внутренняя ошибка сервера",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UserFromEntity(user))
+}
+
+// ListLinks godoc
+// @Summary Список ссылок всех пользователей
+// @Description Возвращает ссылки с поиском по коду и адресу, фильтрами по владельцу и статусу
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param search query string false "Подстрока короткого кода или адреса"
+// @Param user_id query int false "ID владельца"
+// @Param is_active query bool false "Статус ссылки"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Success 200 {object} dto.LinkListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /admin/links [get]
+func (h *adminHandler) ListLinks(c *gin.Context) {
+	var req dto.AdminLinkListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Error("Invalid query params:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid query parameters",
+		})
+		return
+	}
+
+	links, total, err := h.adminUC.ListLinks(c.Request.Context(), entity.LinkFilter{
+		UserID:   req.UserID,
+		Search:   req.Search,
+		IsActive: req.IsActive,
+		Offset:   req.GetOffset(),
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		h.log.Error("Failed to list links:", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "Failed to get links",
+		})
+		return
+	}
+
+	items := make([]*dto.LinkResponse, len(links))
+	for i, link := range links {
+		items[i] = dto.LinkFromEntity(link, h.cfg.URL.BaseURL)
+	}
+
+	nextPage := req.NextPage(total)
+	c.JSON(http.StatusOK, dto.LinkListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     req.Page,
+		Limit:    req.Limit,
+		HasNext:  nextPage != nil,
+		NextPage: nextPage,
+	})
+}
+
+// DisableLink godoc
+// @Summary Отключение ссылки администратором
+// @Description Отключает любую ссылку, например при злоупотреблениях. Владелец не может снять отключение: переходы возвращают 403 LINK_DISABLED
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Success 200 {object} dto.LinkResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /admin/links/{id}/disable [post]
+func (h *adminHandler) DisableLink(c *gin.Context) {
+	h.setLinkActive(c, false)
+}
+
+// EnableLink godoc
+// @Summary Включение ссылки администратором
+// @Description Снимает отключение администратором; приостановка ссылки владельцем сохраняется
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Success 200 {object} dto.LinkResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /admin/links/{id}/enable [post]
+func (h *adminHandler) EnableLink(c *gin.Context) {
+	h.setLinkActive(c, true)
+}
+
+// setLinkActive общая часть DisableLink и EnableLink
+func (h *adminHandler) setLinkActive(c *gin.Context, active bool) {
+	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid link ID",
+		})
+		return
+	}
+
+	link, err := h.adminUC.SetLinkActive(c.Request.Context(), linkID, active)
+	if err != nil {
+		h.log.Error("Failed to change link status:", err)
+		if errors.Is(err, usecase.ErrLinkNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Link not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LinkFromEntity(link, h.cfg.URL.BaseURL))
+}
+
+// GetGlobalStats godoc
+// @Summary Общая статистика сервиса
+// @Description Возвращает количество пользователей, ссылок и кликов по всему сервису
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} entity.GlobalStats
+// @Failure 403 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /admin/stats [get]
+func (h *adminHandler) GetGlobalStats(c *gin.Context) {
+	stats, err := h.adminUC.GetGlobalStats(c.Request.Context())
+	if err != nil {
+		h.log.Error("Ошибка получения общей статистики:", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "Внутренняя ошибка сервера",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetMetrics godoc
+// @Summary Метрики фоновых задач
+// @Description Возвращает счетчики процесса в формате expvar, в том числе link_sweeper: запуски очистки истекших ссылок, пропуски без блокировки, число удаленных и архивированных ссылок, ошибки
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /admin/metrics [get]
+func (h *adminHandler) GetMetrics(c *gin.Context) {
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
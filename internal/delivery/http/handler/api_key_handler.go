@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+type apiKeyHandler struct {
+	apiKeyUC usecase.APIKeyUseCase
+	log      logger.Logger
+}
+
+// NewAPIKeyHandler создает новый handler для API-ключей
+func NewAPIKeyHandler(apiKeyUC usecase.APIKeyUseCase, log logger.Logger) *apiKeyHandler {
+	return &apiKeyHandler{
+		apiKeyUC: apiKeyUC,
+		log:      log,
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary Список API-ключей
+// @Description Возвращает API-ключи текущего пользователя, включая отозванные. Секреты не возвращаются
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /users/me/api-keys [get]
+func (h *apiKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Требуется авторизация",
+		})
+		return
+	}
+
+	keys, err := h.apiKeyUC.List(c.Request.Context(), *userID)
+	if err != nil {
+		h.log.Error("Ошибка получения API-ключей:", err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "Внутренняя ошибка сервера",
+		})
+		return
+	}
+
+	response := make([]*dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		response[i] = dto.APIKeyFromEntity(key)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateAPIKey godoc
+// @Summary Выпуск API-ключа
+// @Description Создает именованный API-ключ с ограниченным набором прав. Ключ возвращается только в этом ответе
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPIKeyRequest true "Имя и права ключа"
+// @Success 201 {object} dto.CreateAPIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /users/me/api-keys [post]
+func (h *apiKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Требуется авторизация",
+		})
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Ошибка привязки запроса:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректный формат запроса",
+		})
+		return
+	}
+
+	scopes := make([]entity.APIKeyScope, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = entity.APIKeyScope(scope)
+	}
+
+	key, rawKey, err := h.apiKeyUC.Create(c.Request.Context(), *userID, req.Name, scopes)
+	if err != nil {
+		h.log.Error("Ошибка создания API-ключа:", err)
+
+		switch err {
+		case usecase.ErrInvalidAPIKeyName, usecase.ErrInvalidScope:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: "Внутренняя ошибка сервера",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateAPIKeyResponse{
+		APIKeyResponse: *dto.APIKeyFromEntity(key),
+		Key:            rawKey,
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Отзыв API-ключа
+// @Description Отзывает API-ключ текущего пользователя; запросы с ним сразу перестают проходить
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ключа"
+// @Success 204
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /users/me/api-keys/{id} [delete]
+func (h *apiKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Требуется авторизация",
+		})
+		return
+	}
+
+	keyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректный ID ключа",
+		})
+		return
+	}
+
+	if err := h.apiKeyUC.Revoke(c.Request.Context(), *userID, keyID); err != nil {
+		h.log.Error("Ошибка отзыва API-ключа:", err)
+
+		switch err {
+		case usecase.ErrAPIKeyNotFound:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: "Внутренняя ошибка сервера",
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -61,7 +61,7 @@ func (h *authHandler) Register(c *gin.Context) {
 	}
 
 	// Сразу логиним после регистрации
-	_, token, err := h.userUC.Login(c.Request.Context(), req.Email, req.Password)
+	_, tokens, err := h.userUC.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
 		h.log.Error("Ошибка входа после регистрации:", err)
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
@@ -70,9 +70,7 @@ func (h *authHandler) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.AuthResponse{
-		Token: token,
-	})
+	c.JSON(http.StatusCreated, authResponse(tokens))
 }
 
 // Login godoc
@@ -95,7 +93,7 @@ func (h *authHandler) Login(c *gin.Context) {
 		return
 	}
 
-	_, token, err := h.userUC.Login(c.Request.Context(), req.Email, req.Password)
+	_, tokens, err := h.userUC.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
 		h.log.Error("Ошибка входа:", err)
 
@@ -115,7 +113,96 @@ func (h *authHandler) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.AuthResponse{
-		Token: token,
-	})
+	c.JSON(http.StatusOK, authResponse(tokens))
+}
+
+// Refresh godoc
+// @Summary Обновление токенов
+// @Description Обменивает refresh-токен на новую пару токенов. Старый refresh-токен становится недействительным
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshRequest true "Refresh-токен"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *authHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Ошибка привязки запроса:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректный формат запроса",
+		})
+		return
+	}
+
+	tokens, err := h.userUC.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		h.log.Error("Ошибка обновления токенов:", err)
+
+		switch err {
+		case usecase.ErrInvalidRefreshToken:
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error: err.Error(),
+				Code:  "INVALID_REFRESH_TOKEN",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: "Внутренняя ошибка сервера",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse(tokens))
+}
+
+// Logout godoc
+// @Summary Выход из системы
+// @Description Отзывает refresh-токен. При all=true завершает все сессии пользователя, включая выданные access-токены
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LogoutRequest true "Refresh-токен"
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/logout [post]
+func (h *authHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Ошибка привязки запроса:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректный формат запроса",
+		})
+		return
+	}
+
+	if err := h.userUC.Logout(c.Request.Context(), req.RefreshToken, req.All); err != nil {
+		h.log.Error("Ошибка выхода:", err)
+
+		switch err {
+		case usecase.ErrInvalidRefreshToken:
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error: err.Error(),
+				Code:  "INVALID_REFRESH_TOKEN",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: "Внутренняя ошибка сервера",
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// authResponse преобразует пару токенов в DTO ответа
+func authResponse(tokens *usecase.TokenPair) dto.AuthResponse {
+	return dto.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+	}
 }
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/config"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+type dashboardHandler struct {
+	dashboardUC usecase.DashboardUseCase
+	log         logger.Logger
+	cfg         *config.Config
+}
+
+// NewDashboardHandler создает новый handler для аналитики аккаунта
+func NewDashboardHandler(dashboardUC usecase.DashboardUseCase, log logger.Logger, cfg *config.Config) *dashboardHandler {
+	return &dashboardHandler{
+		dashboardUC: dashboardUC,
+		log:         log,
+		cfg:         cfg,
+	}
+}
+
+// GetDashboard godoc
+// @Summary Аналитика по всем ссылкам
+// @Description Возвращает клики по всем ссылкам пользователя во времени, топ ссылок, стран и источников за период и сравнение с предыдущим периодом той же длины
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param from query string false "Дата начала периода (RFC3339)"
+// @Param to query string false "Дата конца периода (RFC3339)"
+// @Param granularity query string false "Ширина интервала временного ряда (hour, day, week, month)" default(day)
+// @Param tz query string false "Часовой пояс IANA для границ интервалов, например Europe/Moscow" default(UTC)
+// @Param include_bots query bool false "Учитывать клики ботов" default(false)
+// @Success 200 {object} dto.DashboardResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /users/me/dashboard [get]
+func (h *dashboardHandler) GetDashboard(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Требуется авторизация",
+		})
+		return
+	}
+
+	query, err := parseStatsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Некорректные параметры запроса: " + err.Error(),
+		})
+		return
+	}
+
+	dashboard, err := h.dashboardUC.GetDashboard(c.Request.Context(), *userID, query)
+	if err != nil {
+		h.log.Error("Ошибка получения аналитики аккаунта:", err)
+
+		switch {
+		case errors.Is(err, usecase.ErrInvalidGranularity), errors.Is(err, usecase.ErrTimelineTooLong):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: "Некорректные параметры запроса: " + err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: "Внутренняя ошибка сервера",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DashboardFromEntity(dashboard, h.cfg.URL.BaseURL))
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/config"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+type folderHandler struct {
+	folderUC usecase.FolderUseCase
+	log      logger.Logger
+	cfg      *config.Config
+}
+
+// NewFolderHandler создает новый handler для папок
+func NewFolderHandler(folderUC usecase.FolderUseCase, log logger.Logger, cfg *config.Config) *folderHandler {
+	return &folderHandler{
+		folderUC: folderUC,
+		log:      log,
+		cfg:      cfg,
+	}
+}
+
+// ListFolders godoc
+// @Summary Список папок
+// @Description Возвращает папки текущего пользователя по алфавиту
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.FolderResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /folders [get]
+func (h *folderHandler) ListFolders(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	folders, err := h.folderUC.List(c.Request.Context(), *userID)
+	if err != nil {
+		h.log.Error("Failed to list folders:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	response := make([]*dto.FolderResponse, len(folders))
+	for i, folder := range folders {
+		response[i] = dto.FolderFromEntity(folder)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateFolder godoc
+// @Summary Создание папки
+// @Description Создает папку с уникальным для пользователя именем
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param request body dto.FolderRequest true "Имя папки"
+// @Success 201 {object} dto.FolderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /folders [post]
+func (h *folderHandler) CreateFolder(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req dto.FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	folder, err := h.folderUC.Create(c.Request.Context(), *userID, req.Name)
+	if err != nil {
+		h.log.Error("Failed to create folder:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.FolderFromEntity(folder))
+}
+
+// RenameFolder godoc
+// @Summary Переименование папки
+// @Description Меняет имя папки текущего пользователя
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param id path int true "ID папки"
+// @Param request body dto.FolderRequest true "Новое имя папки"
+// @Success 200 {object} dto.FolderResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /folders/{id} [put]
+func (h *folderHandler) RenameFolder(c *gin.Context) {
+	folderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid folder ID"})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req dto.FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	folder, err := h.folderUC.Rename(c.Request.Context(), *userID, folderID, req.Name)
+	if err != nil {
+		h.log.Error("Failed to rename folder:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FolderFromEntity(folder))
+}
+
+// DeleteFolder godoc
+// @Summary Удаление папки
+// @Description Удаляет папку; ссылки из нее не удаляются и остаются без папки
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param id path int true "ID папки"
+// @Success 204
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /folders/{id} [delete]
+func (h *folderHandler) DeleteFolder(c *gin.Context) {
+	folderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid folder ID"})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.folderUC.Delete(c.Request.Context(), *userID, folderID); err != nil {
+		h.log.Error("Failed to delete folder:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MoveLink godoc
+// @Summary Перемещение ссылки в папку
+// @Description Помещает ссылку в папку текущего пользователя; folder_id: null убирает ссылку из папки
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Param request body dto.MoveLinkRequest true "Папка"
+// @Success 200 {object} dto.LinkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/{id}/folder [put]
+func (h *folderHandler) MoveLink(c *gin.Context) {
+	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid link ID"})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req dto.MoveLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	link, err := h.folderUC.MoveLink(c.Request.Context(), *userID, linkID, req.FolderID)
+	if err != nil {
+		h.log.Error("Failed to move link:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LinkFromEntity(link, h.cfg.URL.BaseURL))
+}
+
+// respondOrganizerError переводит ошибки папок и тегов в HTTP-статусы
+func respondOrganizerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Forbidden"})
+	case errors.Is(err, usecase.ErrLinkNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Link not found"})
+	case errors.Is(err, usecase.ErrFolderNotFound), errors.Is(err, usecase.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, usecase.ErrFolderExists), errors.Is(err, usecase.ErrTagExists):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, usecase.ErrInvalidFolderName), errors.Is(err, usecase.ErrInvalidTagName),
+		errors.Is(err, usecase.ErrTooManyTags):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Internal server error"})
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
 	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/config"
 	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
 	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
@@ -16,14 +16,16 @@ import (
 
 type linkHandler struct {
 	linkUC usecase.LinkUseCase
+	tagUC  usecase.TagUseCase
 	log    logger.Logger
 	cfg    *config.Config
 }
 
 // NewLinkHandler создает новый handler для работы со ссылками
-func NewLinkHandler(linkUC usecase.LinkUseCase, log logger.Logger, cfg *config.Config) *linkHandler {
+func NewLinkHandler(linkUC usecase.LinkUseCase, tagUC usecase.TagUseCase, log logger.Logger, cfg *config.Config) *linkHandler {
 	return &linkHandler{
 		linkUC: linkUC,
+		tagUC:  tagUC,
 		log:    log,
 		cfg:    cfg,
 	}
@@ -52,7 +54,7 @@ func (h *linkHandler) CreateLink(c *gin.Context) {
 
 	userID := getUserID(c)
 
-	link, err := h.linkUC.CreateLink(c.Request.Context(), req.URL, userID, req.CustomCode, req.ExpiresAt)
+	link, err := h.linkUC.CreateLink(c.Request.Context(), userID, linkInputFromRequest(req))
 	if err != nil {
 		h.log.Error("Failed to create link:", err)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -64,24 +66,106 @@ func (h *linkHandler) CreateLink(c *gin.Context) {
 	c.JSON(http.StatusCreated, dto.LinkFromEntity(link, h.cfg.URL.BaseURL))
 }
 
+// CreateLinksBatch godoc
+// @Summary Пакетное создание ссылок
+// @Description Создает до 500 ссылок за один запрос. В режиме atomic (по умолчанию) ссылки создаются в одной транзакции и только если все элементы корректны. В режиме partial создаются все корректные элементы
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param request body dto.BatchCreateLinksRequest true "Ссылки и режим создания"
+// @Success 201 {object} dto.BatchCreateLinksResponse
+// @Success 207 {object} dto.BatchCreateLinksResponse
+// @Failure 400 {object} dto.BatchCreateLinksResponse
+// @Security Bearer
+// @Router /links/batch [post]
+func (h *linkHandler) CreateLinksBatch(c *gin.Context) {
+	var req dto.BatchCreateLinksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid request format",
+		})
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = "atomic"
+	}
+	atomic := req.Mode == "atomic"
+
+	inputs := make([]usecase.LinkInput, len(req.Links))
+	for i, item := range req.Links {
+		inputs[i] = linkInputFromRequest(item)
+	}
+
+	results, err := h.linkUC.CreateLinks(c.Request.Context(), getUserID(c), inputs, atomic)
+	if err != nil {
+		h.log.Error("Failed to create links batch:", err)
+		h.respondLinkError(c, err)
+		return
+	}
+
+	response := dto.BatchCreateLinksResponse{
+		Mode:    req.Mode,
+		Results: make([]dto.BatchLinkResultResponse, len(results)),
+	}
+	for i, result := range results {
+		item := dto.BatchLinkResultResponse{Index: i}
+		switch {
+		case result.Err != nil:
+			item.Status = "failed"
+			item.Error = linkErrorMessage(result.Err)
+			response.Failed++
+		case result.Link == nil:
+			// Корректный элемент атомарного пакета, отклоненного из-за других элементов
+			item.Status = "skipped"
+		default:
+			item.Status = "created"
+			item.Link = dto.LinkFromEntity(result.Link, h.cfg.URL.BaseURL)
+			response.Created++
+		}
+		response.Results[i] = item
+	}
+
+	status := http.StatusCreated
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+		if atomic {
+			status = http.StatusBadRequest
+		}
+	}
+
+	c.JSON(status, response)
+}
+
 // GetUserLinks godoc
 // @Summary Получение списка ссылок пользователя
-// @Description Возвращает список всех ссылок текущего пользователя
+// @Description Возвращает страницу ссылок текущего пользователя с поиском, фильтрами и сортировкой, общее количество и номер следующей страницы
 // @Tags links
 // @Accept json
 // @Produce json
+// @Param search query string false "Слова названия, короткого кода или адреса либо подстрока кода или адреса"
+// @Param is_active query bool false "Статус ссылки"
+// @Param expired query bool false "Истек ли срок действия"
+// @Param created_from query string false "Создана не раньше (RFC3339)"
+// @Param created_to query string false "Создана раньше (RFC3339)"
+// @Param min_clicks query int false "Минимальное количество кликов"
+// @Param tag query string false "Имя тега"
+// @Param folder query int false "ID папки"
+// @Param sort query string false "Поле сортировки (created, updated, clicks)" default(created)
+// @Param order query string false "Порядок сортировки (asc, desc)" default(desc)
 // @Param page query int false "Номер страницы" default(1)
 // @Param limit query int false "Количество элементов на странице" default(20)
-// @Success 200 {array} dto.LinkResponse
+// @Success 200 {object} dto.LinkListResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Security Bearer
 // @Router /links [get]
 func (h *linkHandler) GetUserLinks(c *gin.Context) {
-	var pagination dto.PaginationRequest
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		h.log.Error("Invalid pagination params:", err)
+	var req dto.LinkListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Error("Invalid query params:", err)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error: "Invalid pagination parameters",
+			Error: "Invalid query parameters",
 		})
 		return
 	}
@@ -94,21 +178,40 @@ func (h *linkHandler) GetUserLinks(c *gin.Context) {
 		return
 	}
 
-	links, err := h.linkUC.GetUserLinks(c.Request.Context(), *userID, pagination.GetOffset(), pagination.Limit)
+	links, total, err := h.linkUC.GetUserLinks(c.Request.Context(), *userID, entity.LinkFilter{
+		Search:      req.Search,
+		IsActive:    req.IsActive,
+		Expired:     req.Expired,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		MinClicks:   req.MinClicks,
+		Tag:         req.Tag,
+		FolderID:    req.Folder,
+		Sort:        entity.LinkSort(req.Sort),
+		Ascending:   req.Order == "asc",
+		Offset:      req.GetOffset(),
+		Limit:       req.Limit,
+	})
 	if err != nil {
 		h.log.Error("Failed to get user links:", err)
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error: "Failed to get links",
-		})
+		h.respondLinkError(c, err)
 		return
 	}
 
-	response := make([]*dto.LinkResponse, len(links))
+	items := make([]*dto.LinkResponse, len(links))
 	for i, link := range links {
-		response[i] = dto.LinkFromEntity(link, h.cfg.URL.BaseURL)
+		items[i] = dto.LinkFromEntity(link, h.cfg.URL.BaseURL)
 	}
 
-	c.JSON(http.StatusOK, response)
+	nextPage := req.NextPage(total)
+	c.JSON(http.StatusOK, dto.LinkListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     req.Page,
+		Limit:    req.Limit,
+		HasNext:  nextPage != nil,
+		NextPage: nextPage,
+	})
 }
 
 // GetLink godoc
@@ -151,7 +254,7 @@ func (h *linkHandler) GetLink(c *gin.Context) {
 
 // UpdateLink godoc
 // @Summary Обновление ссылки
-// @Description Обновляет информацию о ссылке
+// @Description Меняет переданные поля ссылки, отсутствующие остаются как есть. Срок действия и время открытия снимаются через PATCH со значением null
 // @Tags links
 // @Accept json
 // @Produce json
@@ -162,15 +265,51 @@ func (h *linkHandler) GetLink(c *gin.Context) {
 // @Security Bearer
 // @Router /links/{id} [put]
 func (h *linkHandler) UpdateLink(c *gin.Context) {
-	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
-	if err != nil {
+	var req dto.UpdateLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error: "Invalid link ID",
+			Error: "Invalid request format",
 		})
 		return
 	}
 
-	var req dto.UpdateLinkRequest
+	h.applyLinkUpdate(c, linkUpdateFromPut(req))
+}
+
+// linkUpdateFromPut переводит тело PUT в изменения ссылки: отсутствующие поля не меняются
+func linkUpdateFromPut(req dto.UpdateLinkRequest) usecase.LinkUpdate {
+	update := usecase.LinkUpdate{
+		Title:       req.Title,
+		ExpiresAt:   req.ExpiresAt,
+		StartsAt:    req.StartsAt,
+		IsActive:    req.IsActive,
+		Password:    req.Password,
+		FallbackURL: req.FallbackURL,
+	}
+	if req.URL != "" {
+		update.OriginalURL = &req.URL
+	}
+	if req.CustomCode != "" {
+		update.ShortCode = &req.CustomCode
+	}
+	return update
+}
+
+// PatchLink godoc
+// @Summary Частичное обновление ссылки
+// @Description Меняет только переданные поля. expires_at: null снимает срок действия, starts_at: null - время открытия
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Param request body dto.PatchLinkRequest true "Изменяемые поля"
+// @Success 200 {object} dto.LinkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/{id} [patch]
+func (h *linkHandler) PatchLink(c *gin.Context) {
+	var req dto.PatchLinkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.log.Error("Failed to bind request:", err)
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -179,6 +318,50 @@ func (h *linkHandler) UpdateLink(c *gin.Context) {
 		return
 	}
 
+	if req.URL.Null || req.CustomCode.Null || req.IsActive.Null {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Only expires_at, starts_at, title, password and fallback_url can be null",
+		})
+		return
+	}
+
+	title := req.Title.Ptr()
+	if req.Title.Null {
+		title = new(string)
+	}
+	password := req.Password.Ptr()
+	if req.Password.Null {
+		password = new(string)
+	}
+	fallbackURL := req.FallbackURL.Ptr()
+	if req.FallbackURL.Null {
+		fallbackURL = new(string)
+	}
+
+	h.applyLinkUpdate(c, usecase.LinkUpdate{
+		OriginalURL:    req.URL.Ptr(),
+		ShortCode:      req.CustomCode.Ptr(),
+		Title:          title,
+		ExpiresAt:      req.ExpiresAt.Ptr(),
+		ClearExpiresAt: req.ExpiresAt.Null,
+		StartsAt:       req.StartsAt.Ptr(),
+		ClearStartsAt:  req.StartsAt.Null,
+		IsActive:       req.IsActive.Ptr(),
+		Password:       password,
+		FallbackURL:    fallbackURL,
+	})
+}
+
+// applyLinkUpdate общая часть UpdateLink и PatchLink
+func (h *linkHandler) applyLinkUpdate(c *gin.Context, update usecase.LinkUpdate) {
+	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid link ID",
+		})
+		return
+	}
+
 	userID := getUserID(c)
 	if userID == nil {
 		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
@@ -187,19 +370,80 @@ func (h *linkHandler) UpdateLink(c *gin.Context) {
 		return
 	}
 
-	err = h.linkUC.UpdateLink(c.Request.Context(), linkID, *userID, req.ExpiresAt)
+	link, err := h.linkUC.UpdateLink(c.Request.Context(), linkID, *userID, update)
 	if err != nil {
 		h.log.Error("Failed to update link:", err)
 		h.respondLinkError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Link updated successfully"})
+	c.JSON(http.StatusOK, dto.LinkFromEntity(link, h.cfg.URL.BaseURL))
+}
+
+// ActivateLink godoc
+// @Summary Включение ссылки
+// @Description Возобновляет работу приостановленной ссылки
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/{id}/activate [post]
+func (h *linkHandler) ActivateLink(c *gin.Context) {
+	h.setLinkActive(c, true)
+}
+
+// DeactivateLink godoc
+// @Summary Приостановка ссылки
+// @Description Отключает ссылку без удаления, переходы по ней перестают работать
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/{id}/deactivate [post]
+func (h *linkHandler) DeactivateLink(c *gin.Context) {
+	h.setLinkActive(c, false)
+}
+
+// setLinkActive общая часть ActivateLink и DeactivateLink
+func (h *linkHandler) setLinkActive(c *gin.Context, active bool) {
+	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid link ID",
+		})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return
+	}
+
+	if err := h.linkUC.SetLinkActive(c.Request.Context(), linkID, *userID, active); err != nil {
+		h.log.Error("Failed to change link status:", err)
+		h.respondLinkError(c, err)
+		return
+	}
+
+	message := "Link deactivated successfully"
+	if active {
+		message = "Link activated successfully"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
 }
 
 // DeleteLink godoc
 // @Summary Удаление ссылки
-// @Description Удаляет ссылку
+// @Description Переносит ссылку в корзину: переходы по ней сразу перестают работать, статистика сохраняется. Ссылку можно восстановить, пока корзина не очищена
 // @Tags links
 // @Accept json
 // @Produce json
@@ -235,16 +479,116 @@ func (h *linkHandler) DeleteLink(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// GetTrash godoc
+// @Summary Корзина ссылок
+// @Description Возвращает страницу удаленных ссылок текущего пользователя, начиная с удаленных последними. Ссылки окончательно удаляются после срока хранения корзины
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param search query string false "Слова названия, короткого кода или адреса либо подстрока кода или адреса"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(20)
+// @Success 200 {object} dto.LinkListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/trash [get]
+func (h *linkHandler) GetTrash(c *gin.Context) {
+	var req dto.LinkTrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Error("Invalid query params:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid query parameters",
+		})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return
+	}
+
+	links, total, err := h.linkUC.GetTrash(c.Request.Context(), *userID, entity.LinkFilter{
+		Search: req.Search,
+		Offset: req.GetOffset(),
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		h.log.Error("Failed to get deleted links:", err)
+		h.respondLinkError(c, err)
+		return
+	}
+
+	items := make([]*dto.LinkResponse, len(links))
+	for i, link := range links {
+		items[i] = dto.LinkFromEntity(link, h.cfg.URL.BaseURL)
+	}
+
+	nextPage := req.NextPage(total)
+	c.JSON(http.StatusOK, dto.LinkListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     req.Page,
+		Limit:    req.Limit,
+		HasNext:  nextPage != nil,
+		NextPage: nextPage,
+	})
+}
+
+// RestoreLink godoc
+// @Summary Восстановление ссылки
+// @Description Возвращает ссылку из корзины вместе с ее статистикой, переходы по ней снова работают
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Success 200 {object} dto.LinkResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/{id}/restore [post]
+func (h *linkHandler) RestoreLink(c *gin.Context) {
+	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid link ID",
+		})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return
+	}
+
+	link, err := h.linkUC.RestoreLink(c.Request.Context(), linkID, *userID)
+	if err != nil {
+		h.log.Error("Failed to restore link:", err)
+		h.respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LinkFromEntity(link, h.cfg.URL.BaseURL))
+}
+
 // GetLinkStats godoc
 // @Summary Получение статистики по ссылке
-// @Description Возвращает статистику переходов по ссылке
+// @Description Возвращает статистику переходов по ссылке. Клики ботов и сервисов предпросмотра по умолчанию не учитываются, их количество возвращается в bot_clicks
 // @Tags links
 // @Accept json
 // @Produce json
 // @Param id path int true "ID ссылки"
 // @Param from query string false "Дата начала периода (RFC3339)"
 // @Param to query string false "Дата конца периода (RFC3339)"
+// @Param include_bots query bool false "Учитывать клики ботов" default(false)
+// @Param granularity query string false "Ширина интервала временного ряда (hour, day, week, month)" default(day)
+// @Param tz query string false "Часовой пояс IANA для границ интервалов, например Europe/Moscow" default(UTC)
 // @Success 200 {object} dto.LinkStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Security Bearer
 // @Router /links/{id}/stats [get]
@@ -265,57 +609,183 @@ func (h *linkHandler) GetLinkStats(c *gin.Context) {
 		return
 	}
 
-	// Парсинг дат из query параметров
-	from := time.Now().AddDate(0, -1, 0) // По умолчанию - месяц назад
-	to := time.Now()
+	query, err := parseStatsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
 
-	if fromStr := c.Query("from"); fromStr != "" {
-		if parsedFrom, err := time.Parse(time.RFC3339, fromStr); err == nil {
-			from = parsedFrom
-		}
+	stats, err := h.linkUC.GetLinkStats(c.Request.Context(), linkID, *userID, query)
+	if err != nil {
+		h.log.Error("Failed to get link stats:", err)
+		h.respondLinkError(c, err)
+		return
 	}
 
-	if toStr := c.Query("to"); toStr != "" {
-		if parsedTo, err := time.Parse(time.RFC3339, toStr); err == nil {
-			to = parsedTo
-		}
+	c.JSON(http.StatusOK, dto.LinkStatsFromEntity(stats))
+}
+
+// ListClicks godoc
+// @Summary Журнал кликов по ссылке
+// @Description Возвращает отдельные клики от новых к старым. Для следующей страницы передайте next_cursor из ответа в параметре cursor. IP посетителей возвращаются только в виде хеша
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Param from query string false "Начало периода включительно (RFC3339)"
+// @Param to query string false "Конец периода не включительно (RFC3339)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param limit query int false "Количество кликов на странице" default(50)
+// @Success 200 {object} dto.ClickListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/{id}/clicks [get]
+func (h *linkHandler) ListClicks(c *gin.Context) {
+	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid link ID",
+		})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return
+	}
+
+	var req dto.ClickListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.log.Error("Invalid query params:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Invalid query parameters",
+		})
+		return
 	}
 
-	stats, err := h.linkUC.GetLinkStats(c.Request.Context(), linkID, *userID, from, to)
+	page, err := h.linkUC.ListClicks(c.Request.Context(), linkID, *userID, usecase.ClickQuery{
+		From:   req.From,
+		To:     req.To,
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+	})
 	if err != nil {
-		h.log.Error("Failed to get link stats:", err)
+		h.log.Error("Failed to list clicks:", err)
 		h.respondLinkError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.LinkStatsFromEntity(stats))
+	items := make([]*dto.ClickResponse, len(page.Clicks))
+	for i, click := range page.Clicks {
+		items[i] = dto.ClickFromEntity(click)
+	}
+
+	c.JSON(http.StatusOK, dto.ClickListResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+	})
 }
 
 // RedirectShortURL godoc
 // @Summary Переход по короткой ссылке
-// @Description Перенаправляет на оригинальный URL и записывает статистику
+// @Description Перенаправляет на оригинальный URL и записывает статистику. Несуществующий код, истекшая, отключенная и исчерпанная ссылки перенаправляются на резервный адрес (fallback_url ссылки, аккаунта или LINK_FALLBACK_URL), а без него браузер получает HTML-страницу ошибки. Для ссылки с паролем возвращает 401: браузеру - форму ввода пароля, остальным клиентам - JSON с кодом PASSWORD_REQUIRED
 // @Tags redirect
+// @Produce json,html
 // @Param code path string true "Короткий код"
 // @Success 302
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
 // @Router /{code} [get]
 func (h *linkHandler) RedirectShortURL(c *gin.Context) {
+	h.redirect(c, "", http.StatusFound)
+}
+
+// UnlockShortURL godoc
+// @Summary Переход по ссылке с паролем
+// @Description Проверяет пароль ссылки и перенаправляет на оригинальный URL. Неверный пароль возвращает 401 с кодом INVALID_PASSWORD (браузеру - форму повторно), после нескольких неудачных попыток ссылка временно блокируется с ответом 429
+// @Tags redirect
+// @Accept x-www-form-urlencoded
+// @Produce json,html
+// @Param code path string true "Короткий код"
+// @Param password formData string true "Пароль ссылки"
+// @Success 303
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Router /{code} [post]
+func (h *linkHandler) UnlockShortURL(c *gin.Context) {
+	// 303 заставляет браузер перейти на оригинальный URL методом GET
+	h.redirect(c, c.PostForm("password"), http.StatusSeeOther)
+}
+
+// redirect общая часть RedirectShortURL и UnlockShortURL
+func (h *linkHandler) redirect(c *gin.Context, password string, status int) {
 	shortCode := c.Param("code")
 
 	link, err := h.linkUC.RecordClick(
 		c.Request.Context(),
 		shortCode,
+		password,
 		c.ClientIP(),
 		c.Request.UserAgent(),
 		c.Request.Referer(),
 	)
+	if errors.Is(err, usecase.ErrPasswordRequired) || errors.Is(err, usecase.ErrWrongPassword) {
+		respondPasswordPrompt(c, err)
+		return
+	}
 	if err != nil {
 		h.log.Error("Failed to process redirect:", err)
-		h.respondLinkError(c, err)
+		h.respondRedirectError(c, shortCode, err)
 		return
 	}
 
-	c.Redirect(http.StatusFound, link.OriginalURL)
+	c.Redirect(status, link.OriginalURL)
+}
+
+// respondRedirectError отвечает на переход по ссылке, которую нельзя открыть. Несуществующий код,
+// истекшая, отключенная или исчерпанная ссылка ведут на резервный адрес ссылки, ее владельца
+// или глобальный; без резервного адреса браузеру отдается страница ошибки, остальным клиентам - JSON
+func (h *linkHandler) respondRedirectError(c *gin.Context, shortCode string, err error) {
+	if errors.Is(err, usecase.ErrLinkNotFound) || errors.Is(err, usecase.ErrLinkExpired) ||
+		errors.Is(err, usecase.ErrLinkInactive) || errors.Is(err, usecase.ErrLinkExhausted) {
+		// У несуществующего кода нет ни ссылки, ни владельца: лишний запрос в базу не нужен
+		var fallbackURL string
+		if !errors.Is(err, usecase.ErrLinkNotFound) {
+			var fallbackErr error
+			fallbackURL, fallbackErr = h.linkUC.GetFallbackURL(c.Request.Context(), shortCode)
+			if fallbackErr != nil {
+				h.log.Error("Failed to get fallback URL:", fallbackErr)
+			}
+		}
+		if fallbackURL == "" {
+			fallbackURL = h.cfg.Links.FallbackURL
+		}
+		if fallbackURL != "" {
+			c.Redirect(http.StatusFound, fallbackURL)
+			return
+		}
+	}
+
+	status, response := h.linkErrorResponse(err)
+	if wantsHTML(c) {
+		renderErrorPage(c, status, response.Error)
+		return
+	}
+	c.JSON(status, response)
 }
 
 // getUserID извлекает ID пользователя из контекста
@@ -328,18 +798,82 @@ func getUserID(c *gin.Context) *int64 {
 	return nil
 }
 
+// linkInputFromRequest преобразует запрос на создание ссылки во входные данные use case
+func linkInputFromRequest(req dto.CreateLinkRequest) usecase.LinkInput {
+	input := usecase.LinkInput{
+		OriginalURL:         req.URL,
+		CustomCode:          req.CustomCode,
+		Title:               req.Title,
+		ExpiresAt:           req.ExpiresAt,
+		StartsAt:            req.StartsAt,
+		Password:            req.Password,
+		MaxClicks:           req.MaxClicks,
+		DeleteWhenExhausted: req.DeleteWhenExhausted,
+		FallbackURL:         req.FallbackURL,
+	}
+	if req.UTM != nil {
+		input.UTM = entity.UTM{
+			Source:   req.UTM.Source,
+			Medium:   req.UTM.Medium,
+			Campaign: req.UTM.Campaign,
+			Term:     req.UTM.Term,
+			Content:  req.UTM.Content,
+		}
+	}
+	return input
+}
+
+// linkErrorMessage возвращает текст ошибки, который можно показать клиенту
+func linkErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrShortCodeExists), errors.Is(err, usecase.ErrExpirationInPast), errors.Is(err, usecase.ErrInvalidURL),
+		errors.Is(err, usecase.ErrInvalidShortCode), errors.Is(err, usecase.ErrInvalidUTM),
+		errors.Is(err, usecase.ErrInvalidTitle), errors.Is(err, usecase.ErrInvalidLinkPassword),
+		errors.Is(err, usecase.ErrInvalidMaxClicks), errors.Is(err, usecase.ErrInvalidWindow),
+		errors.Is(err, usecase.ErrInvalidFallbackURL), errors.Is(err, usecase.ErrInvalidTagName),
+		errors.Is(err, usecase.ErrTooManyTags):
+		return err.Error()
+	default:
+		return "Internal server error"
+	}
+}
+
 // respondLinkError переводит бизнес-ошибки в HTTP-статусы
 func (h *linkHandler) respondLinkError(c *gin.Context, err error) {
+	status, response := h.linkErrorResponse(err)
+	c.JSON(status, response)
+}
+
+// linkErrorResponse возвращает HTTP-статус и тело ответа для бизнес-ошибки
+func (h *linkHandler) linkErrorResponse(err error) (int, dto.ErrorResponse) {
 	switch {
 	case errors.Is(err, usecase.ErrUnauthorized):
-		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "Forbidden"})
+		return http.StatusForbidden, dto.ErrorResponse{Error: "Forbidden"}
 	case errors.Is(err, usecase.ErrLinkExpired):
-		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Link expired"})
+		return http.StatusConflict, dto.ErrorResponse{Error: "Link expired"}
+	case errors.Is(err, usecase.ErrLinkInactive):
+		return http.StatusLocked, dto.ErrorResponse{Error: "Link is inactive", Code: "LINK_INACTIVE"}
+	case errors.Is(err, usecase.ErrLinkDisabled):
+		return http.StatusForbidden, dto.ErrorResponse{Error: "Link is disabled by an administrator", Code: "LINK_DISABLED"}
+	case errors.Is(err, usecase.ErrLinkNotStarted):
+		status := h.cfg.Links.NotStartedStatus
+		if status < 400 || status > 599 {
+			status = http.StatusForbidden
+		}
+		return status, dto.ErrorResponse{Error: h.cfg.Links.NotStartedMessage, Code: "LINK_NOT_STARTED"}
+	case errors.Is(err, usecase.ErrLinkExhausted):
+		return http.StatusGone, dto.ErrorResponse{Error: "Link has reached its click limit", Code: "LINK_EXHAUSTED"}
 	case errors.Is(err, usecase.ErrLinkNotFound):
-		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Link not found"})
-	case errors.Is(err, usecase.ErrShortCodeExists), errors.Is(err, usecase.ErrExpirationInPast), errors.Is(err, usecase.ErrInvalidURL):
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return http.StatusNotFound, dto.ErrorResponse{Error: "Link not found"}
+	case errors.Is(err, usecase.ErrShortCodeExists), errors.Is(err, usecase.ErrExpirationInPast), errors.Is(err, usecase.ErrInvalidURL),
+		errors.Is(err, usecase.ErrInvalidShortCode), errors.Is(err, usecase.ErrInvalidCursor),
+		errors.Is(err, usecase.ErrInvalidGranularity), errors.Is(err, usecase.ErrTimelineTooLong),
+		errors.Is(err, usecase.ErrInvalidUTM), errors.Is(err, usecase.ErrInvalidTitle),
+		errors.Is(err, usecase.ErrInvalidSort), errors.Is(err, usecase.ErrInvalidLinkPassword),
+		errors.Is(err, usecase.ErrInvalidMaxClicks), errors.Is(err, usecase.ErrInvalidWindow),
+		errors.Is(err, usecase.ErrInvalidFallbackURL):
+		return http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()}
 	default:
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Internal server error"})
+		return http.StatusInternalServerError, dto.ErrorResponse{Error: "Internal server error"}
 	}
 }
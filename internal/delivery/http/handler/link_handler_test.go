@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkUpdateFromPut(t *testing.T) {
+	t.Run("Success - Absent fields are left unchanged", func(t *testing.T) {
+		inactive := false
+
+		update := linkUpdateFromPut(dto.UpdateLinkRequest{IsActive: &inactive})
+
+		assert.Equal(t, &inactive, update.IsActive)
+		assert.Nil(t, update.OriginalURL)
+		assert.Nil(t, update.ShortCode)
+		assert.Nil(t, update.Title)
+		assert.Nil(t, update.ExpiresAt)
+		assert.False(t, update.ClearExpiresAt)
+		assert.Nil(t, update.StartsAt)
+		assert.False(t, update.ClearStartsAt)
+		assert.Nil(t, update.Password)
+		assert.Nil(t, update.FallbackURL)
+	})
+
+	t.Run("Success - Present fields are passed on", func(t *testing.T) {
+		expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		empty := ""
+
+		update := linkUpdateFromPut(dto.UpdateLinkRequest{
+			URL:         "https://example.com/new",
+			CustomCode:  "promo",
+			Title:       &empty,
+			ExpiresAt:   &expiresAt,
+			FallbackURL: &empty,
+		})
+
+		assert.Equal(t, "https://example.com/new", *update.OriginalURL)
+		assert.Equal(t, "promo", *update.ShortCode)
+		assert.Equal(t, "", *update.Title)
+		assert.Equal(t, &expiresAt, update.ExpiresAt)
+		assert.Equal(t, "", *update.FallbackURL)
+	})
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+)
+
+const (
+	// maxImportSize ограничивает размер загружаемого CSV
+	maxImportSize = 5 << 20
+	// maxImportRows ограничивает количество строк в одном импорте
+	maxImportRows = 10000
+)
+
+// linkCSVHeader - столбцы CSV экспорта; импорт принимает файл того же формата
+var linkCSVHeader = []string{"short_code", "short_url", "original_url", "clicks", "is_active", "expires_at", "created_at", "tags"}
+
+// csvTagSeparator разделяет имена тегов в столбце tags
+const csvTagSeparator = ";"
+
+// importRow - строка CSV, готовая к созданию ссылки
+type importRow struct {
+	line  int // номер строки в файле
+	input usecase.LinkInput
+}
+
+// ExportLinks godoc
+// @Summary Экспорт ссылок
+// @Description Выгружает все ссылки текущего пользователя в CSV или JSON. Ответ передается потоком
+// @Tags links
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "Формат выгрузки (csv, json)" default(csv)
+// @Success 200 {array} dto.LinkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/export [get]
+func (h *linkHandler) ExportLinks(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: "Unsupported export format",
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("links-%s.%s", time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	var err error
+	if format == "csv" {
+		err = h.exportCSV(c, *userID)
+	} else {
+		err = h.exportJSON(c, *userID)
+	}
+
+	if err != nil {
+		h.log.Error("Failed to export links:", err)
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			h.respondLinkError(c, err)
+		}
+		// Если данные уже отправлены, ответ остается оборванным: статус изменить нельзя
+	}
+}
+
+// exportCSV пишет ссылки в CSV, сбрасывая буфер после каждой страницы
+func (h *linkHandler) exportCSV(c *gin.Context, userID int64) error {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	w := csv.NewWriter(c.Writer)
+
+	started := false
+	err := h.linkUC.ExportLinks(c.Request.Context(), userID, func(link *entity.Link) error {
+		if !started {
+			started = true
+			if err := w.Write(linkCSVHeader); err != nil {
+				return err
+			}
+		}
+
+		expiresAt := ""
+		if link.ExpiresAt != nil {
+			expiresAt = link.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+
+		return w.Write([]string{
+			link.ShortCode,
+			h.cfg.URL.BaseURL + "/" + link.ShortCode,
+			link.OriginalURL,
+			strconv.FormatInt(link.Clicks, 10),
+			strconv.FormatBool(link.IsActive),
+			expiresAt,
+			link.CreatedAt.UTC().Format(time.RFC3339),
+			strings.Join(link.Tags, csvTagSeparator),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if !started {
+		if err := w.Write(linkCSVHeader); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// exportJSON пишет ссылки JSON-массивом по одному элементу
+func (h *linkHandler) exportJSON(c *gin.Context, userID int64) error {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(c.Writer)
+
+	count := 0
+	err := h.linkUC.ExportLinks(c.Request.Context(), userID, func(link *entity.Link) error {
+		sep := ","
+		if count == 0 {
+			sep = "["
+		}
+		count++
+
+		if _, err := io.WriteString(c.Writer, sep); err != nil {
+			return err
+		}
+		return enc.Encode(dto.LinkFromEntity(link, h.cfg.URL.BaseURL))
+	})
+	if err != nil {
+		return err
+	}
+
+	closing := "]"
+	if count == 0 {
+		closing = "[]"
+	}
+	_, err = io.WriteString(c.Writer, closing)
+	return err
+}
+
+// ImportLinks godoc
+// @Summary Импорт ссылок из CSV
+// @Description Создает ссылки из CSV в формате экспорта (файл в поле file формы или тело запроса text/csv). Обязателен столбец original_url; short_code, expires_at (RFC 3339) и tags (имена через ";", недостающие теги создаются) необязательны, остальные столбцы игнорируются. Каждая строка проверяется по правилам создания ссылки, ошибки возвращаются по номерам строк
+// @Tags links
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Produce json
+// @Param file formData file false "CSV файл"
+// @Success 200 {object} dto.ImportLinksResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/import [post]
+func (h *linkHandler) ImportLinks(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "Unauthorized",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: "CSV file is required in the 'file' field",
+			})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			h.log.Error("Failed to open uploaded file:", err)
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: "Failed to read uploaded file",
+			})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	rows, rowErrors, err := parseLinksCSV(body)
+	if err != nil {
+		h.log.Error("Failed to parse CSV:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	inputs := make([]usecase.LinkInput, len(rows))
+	for i, row := range rows {
+		inputs[i] = row.input
+	}
+
+	results, err := h.linkUC.CreateLinks(c.Request.Context(), userID, inputs, false)
+	if err != nil {
+		h.log.Error("Failed to import links:", err)
+		h.respondLinkError(c, err)
+		return
+	}
+
+	response := dto.ImportLinksResponse{
+		Total:  len(rows) + len(rowErrors),
+		Errors: rowErrors,
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			response.Errors = append(response.Errors, dto.ImportRowError{
+				Row:   rows[i].line,
+				Error: linkErrorMessage(result.Err),
+			})
+			continue
+		}
+		response.Created++
+
+		// Теги уже проверены вместе со строкой, поэтому здесь возможны только сбои базы
+		if tags := rows[i].input.Tags; len(tags) > 0 {
+			if _, err := h.tagUC.SetLinkTags(c.Request.Context(), *userID, result.Link.ID, tags); err != nil {
+				h.log.Error("Failed to set tags of imported link:", err)
+				response.Errors = append(response.Errors, dto.ImportRowError{
+					Row:   rows[i].line,
+					Error: "link created, but its tags were not applied",
+				})
+			}
+		}
+	}
+	response.Failed = response.Total - response.Created
+	if response.Errors == nil {
+		response.Errors = []dto.ImportRowError{}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseLinksCSV читает CSV с заголовком и возвращает разобранные строки вместе с их
+// номерами в файле. Ошибки формата отдельных строк не прерывают разбор
+func parseLinksCSV(r io.Reader) ([]importRow, []dto.ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("CSV file is empty")
+		}
+		return nil, nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	// Excel добавляет BOM в начало UTF-8 файлов
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["original_url"]; !ok {
+		return nil, nil, errors.New("CSV must contain an original_url column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []importRow
+	var rowErrors []dto.ImportRowError
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, dto.ImportRowError{Row: parseErr.StartLine, Error: "malformed CSV row"})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		// FieldPos можно вызывать только после успешного Read
+		line, _ := reader.FieldPos(0)
+
+		if len(rows)+len(rowErrors) >= maxImportRows {
+			return nil, nil, fmt.Errorf("CSV must not contain more than %d rows", maxImportRows)
+		}
+
+		input := usecase.LinkInput{
+			OriginalURL: field(record, "original_url"),
+			CustomCode:  field(record, "short_code"),
+		}
+
+		if value := field(record, "expires_at"); value != "" {
+			expiresAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				rowErrors = append(rowErrors, dto.ImportRowError{Row: line, Error: "invalid expires_at, expected RFC 3339"})
+				continue
+			}
+			input.ExpiresAt = &expiresAt
+		}
+
+		for _, tag := range strings.Split(field(record, "tags"), csvTagSeparator) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				input.Tags = append(input.Tags, tag)
+			}
+		}
+
+		rows = append(rows, importRow{line: line, input: input})
+	}
+
+	return rows, rowErrors, nil
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinksCSV(t *testing.T) {
+	t.Run("Success - Malformed row is reported and the rest is imported", func(t *testing.T) {
+		csvData := "short_code,original_url\n" +
+			"ok1,https://example.com/1\n" +
+			"\"bad\"x,https://example.com/3\n" +
+			"ok2,https://example.com/2\n"
+
+		rows, rowErrors, err := parseLinksCSV(strings.NewReader(csvData))
+
+		assert.NoError(t, err)
+		if assert.Len(t, rows, 2) {
+			assert.Equal(t, 2, rows[0].line)
+			assert.Equal(t, 4, rows[1].line)
+			assert.Equal(t, "https://example.com/2", rows[1].input.OriginalURL)
+		}
+		if assert.Len(t, rowErrors, 1) {
+			assert.Equal(t, 3, rowErrors[0].Row)
+			assert.Equal(t, "malformed CSV row", rowErrors[0].Error)
+		}
+	})
+
+	t.Run("Success - Tags column", func(t *testing.T) {
+		csvData := "original_url,tags\n" +
+			"https://example.com/1,promo; spring\n" +
+			"https://example.com/2,\n"
+
+		rows, rowErrors, err := parseLinksCSV(strings.NewReader(csvData))
+
+		assert.NoError(t, err)
+		assert.Empty(t, rowErrors)
+		if assert.Len(t, rows, 2) {
+			assert.Equal(t, []string{"promo", "spring"}, rows[0].input.Tags)
+			assert.Empty(t, rows[1].input.Tags)
+		}
+	})
+
+	t.Run("Error - Missing original_url column", func(t *testing.T) {
+		_, _, err := parseLinksCSV(strings.NewReader("short_code\nabc\n"))
+
+		assert.Error(t, err)
+	})
+}
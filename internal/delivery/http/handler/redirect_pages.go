@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+)
+
+// passwordPage - форма ввода пароля защищенной ссылки. Форма без action отправляется
+// на тот же адрес, поэтому работает и для /{code}, и для /api/v1/{code}
+var passwordPage = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Password required</title>
+</head>
+<body>
+<form method="post">
+<p>This link is password protected.</p>
+{{if .WrongPassword}}<p role="alert">Wrong password, try again.</p>{{end}}
+<input type="password" name="password" aria-label="Password" required autofocus>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+// errorPage - страница ошибки перехода для браузеров
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Status}} {{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+</body>
+</html>
+`))
+
+// wantsHTML сообщает, что клиент - браузер: в Accept text/html предпочтительнее JSON.
+// Клиенты без Accept или с */* получают JSON
+func wantsHTML(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+}
+
+// renderPage отдает HTML-страницу, которую не нужно кэшировать
+func renderPage(c *gin.Context, status int, page *template.Template, data any) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	_ = page.Execute(c.Writer, data)
+}
+
+// renderErrorPage отдает браузеру страницу ошибки перехода
+func renderErrorPage(c *gin.Context, status int, message string) {
+	renderPage(c, status, errorPage, struct {
+		Status  int
+		Title   string
+		Message string
+	}{status, http.StatusText(status), message})
+}
+
+// respondPasswordPrompt отвечает 401 на переход по защищенной ссылке без верного пароля:
+// браузеру отдается форма ввода пароля, остальным клиентам - JSON с кодом ошибки
+func respondPasswordPrompt(c *gin.Context, err error) {
+	wrongPassword := errors.Is(err, usecase.ErrWrongPassword)
+
+	if wantsHTML(c) {
+		renderPage(c, http.StatusUnauthorized, passwordPage, struct{ WrongPassword bool }{wrongPassword})
+		return
+	}
+
+	code := "PASSWORD_REQUIRED"
+	if wrongPassword {
+		code = "INVALID_PASSWORD"
+	}
+	c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: err.Error(), Code: code})
+}
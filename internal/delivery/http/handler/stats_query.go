@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+)
+
+var (
+	errInvalidIncludeBots = errors.New("invalid include_bots value")
+	errInvalidTimezone    = errors.New("invalid time zone")
+)
+
+// parseStatsQuery читает общие параметры статистики: from, to, include_bots, granularity и tz.
+// Период по умолчанию - последний месяц; некорректные даты заменяются значениями по умолчанию
+func parseStatsQuery(c *gin.Context) (usecase.StatsQuery, error) {
+	query := usecase.StatsQuery{
+		From:        time.Now().AddDate(0, -1, 0),
+		To:          time.Now(),
+		Granularity: entity.Granularity(c.Query("granularity")),
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		if parsedFrom, err := time.Parse(time.RFC3339, fromStr); err == nil {
+			query.From = parsedFrom
+		}
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		if parsedTo, err := time.Parse(time.RFC3339, toStr); err == nil {
+			query.To = parsedTo
+		}
+	}
+
+	if raw := c.Query("include_bots"); raw != "" {
+		includeBots, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errInvalidIncludeBots
+		}
+		query.IncludeBots = includeBots
+	}
+
+	// "Local" - часовой пояс сервера, а не запроса; PostgreSQL его не знает
+	loc, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil || loc == time.Local {
+		return query, errInvalidTimezone
+	}
+	query.Location = loc
+
+	return query, nil
+}
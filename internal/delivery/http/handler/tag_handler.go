@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/config"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+type tagHandler struct {
+	tagUC usecase.TagUseCase
+	log   logger.Logger
+	cfg   *config.Config
+}
+
+// NewTagHandler создает новый handler для тегов
+func NewTagHandler(tagUC usecase.TagUseCase, log logger.Logger, cfg *config.Config) *tagHandler {
+	return &tagHandler{
+		tagUC: tagUC,
+		log:   log,
+		cfg:   cfg,
+	}
+}
+
+// ListTags godoc
+// @Summary Список тегов
+// @Description Возвращает теги текущего пользователя по алфавиту
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.TagResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /tags [get]
+func (h *tagHandler) ListTags(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	tags, err := h.tagUC.List(c.Request.Context(), *userID)
+	if err != nil {
+		h.log.Error("Failed to list tags:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	response := make([]*dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = dto.TagFromEntity(tag)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateTag godoc
+// @Summary Создание тега
+// @Description Создает тег с уникальным для пользователя именем
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body dto.TagRequest true "Имя тега"
+// @Success 201 {object} dto.TagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /tags [post]
+func (h *tagHandler) CreateTag(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	tag, err := h.tagUC.Create(c.Request.Context(), *userID, req.Name)
+	if err != nil {
+		h.log.Error("Failed to create tag:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.TagFromEntity(tag))
+}
+
+// RenameTag godoc
+// @Summary Переименование тега
+// @Description Меняет имя тега; ссылки сохраняют тег под новым именем
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID тега"
+// @Param request body dto.TagRequest true "Новое имя тега"
+// @Success 200 {object} dto.TagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /tags/{id} [put]
+func (h *tagHandler) RenameTag(c *gin.Context) {
+	tagID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid tag ID"})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	tag, err := h.tagUC.Rename(c.Request.Context(), *userID, tagID, req.Name)
+	if err != nil {
+		h.log.Error("Failed to rename tag:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TagFromEntity(tag))
+}
+
+// DeleteTag godoc
+// @Summary Удаление тега
+// @Description Удаляет тег и снимает его со всех ссылок
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID тега"
+// @Success 204
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /tags/{id} [delete]
+func (h *tagHandler) DeleteTag(c *gin.Context) {
+	tagID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid tag ID"})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if err := h.tagUC.Delete(c.Request.Context(), *userID, tagID); err != nil {
+		h.log.Error("Failed to delete tag:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetLinkTags godoc
+// @Summary Установка тегов ссылки
+// @Description Заменяет теги ссылки указанным списком имен. Несуществующие теги создаются, пустой список снимает все теги
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ссылки"
+// @Param request body dto.SetLinkTagsRequest true "Имена тегов"
+// @Success 200 {object} dto.LinkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security Bearer
+// @Router /links/{id}/tags [put]
+func (h *tagHandler) SetLinkTags(c *gin.Context) {
+	linkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid link ID"})
+		return
+	}
+
+	userID := getUserID(c)
+	if userID == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req dto.SetLinkTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind request:", err)
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	link, err := h.tagUC.SetLinkTags(c.Request.Context(), *userID, linkID, req.Tags)
+	if err != nil {
+		h.log.Error("Failed to set link tags:", err)
+		respondOrganizerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LinkFromEntity(link, h.cfg.URL.BaseURL))
+}
@@ -94,7 +94,7 @@ func (h *userHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	err := h.userUC.Update(c.Request.Context(), *userID, req.Email)
+	err := h.userUC.Update(c.Request.Context(), *userID, req.Email, req.FallbackURL)
 	if err != nil {
 		h.log.Error("Ошибка обновления пользователя:", err)
 
@@ -104,7 +104,7 @@ func (h *userHandler) UpdateProfile(c *gin.Context) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error: "Пользователь не найден",
 			})
-		case usecase.ErrInvalidEmail:
+		case usecase.ErrInvalidEmail, usecase.ErrInvalidUserFallback:
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error: err.Error(),
 			})
@@ -137,7 +137,7 @@ func (h *userHandler) UpdateProfile(c *gin.Context) {
 
 // ChangePassword godoc
 // @Summary Изменение пароля
-// @Description Изменяет пароль текущего пользователя
+// @Description Изменяет пароль текущего пользователя и завершает все его сессии
 // @Tags users
 // @Accept json
 // @Produce json
@@ -193,7 +193,7 @@ func (h *userHandler) ChangePassword(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Пароль успешно изменен",
+		"message": "Пароль успешно изменен, войдите заново",
 	})
 }
 
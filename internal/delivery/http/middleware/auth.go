@@ -1,18 +1,27 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
 	"github.com/raison-collab/LinkShorternetBackend/pkg/utils"
 )
 
-// Auth создает middleware для проверки JWT токена
-func Auth(jwtSecret string) gin.HandlerFunc {
+// Auth создает middleware для проверки JWT токена или персонального API-ключа.
+// Для JWT помимо подписи проверяется версия токена пользователя, поэтому
+// отозванные сессии перестают работать сразу
+func Auth(jwtSecret string, userUC usecase.UserUseCase, apiKeyUC usecase.APIKeyUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
+		// API-ключ можно передать в X-API-Key или в Authorization вместо JWT
+		authHeader := c.GetHeader("X-API-Key")
+		if authHeader == "" {
+			authHeader = c.GetHeader("Authorization")
+		}
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
 				Error: "Требуется заголовок авторизации",
@@ -29,6 +38,12 @@ func Auth(jwtSecret string) gin.HandlerFunc {
 		} else {
 			token = authHeader
 		}
+
+		if strings.HasPrefix(token, usecase.APIKeyPrefix) {
+			authenticateAPIKey(c, apiKeyUC, token)
+			return
+		}
+
 		claims, err := utils.ValidateJWT(token, jwtSecret)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
@@ -39,11 +54,129 @@ func Auth(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		// Сохраняем claims в контексте для использования в handlers
-		c.Set("claims", claims)
-		c.Set("userID", claims.UserID)
-		c.Set("userEmail", claims.Email)
+		user, err := userUC.Authenticate(c.Request.Context(), claims)
+		if err != nil {
+			if errors.Is(err, usecase.ErrSessionRevoked) {
+				c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error: "Сессия завершена, войдите заново",
+					Code:  "TOKEN_REVOKED",
+				})
+			} else {
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+					Error: "Внутренняя ошибка сервера",
+				})
+			}
+			c.Abort()
+			return
+		}
 
+		// Роль берем из базы, чтобы ее смена применялась без повторного входа
+		claims.Role = string(user.Role)
+
+		setClaims(c, claims)
 		c.Next()
 	}
 }
+
+// authenticateAPIKey проверяет API-ключ и заполняет контекст так же, как для JWT
+func authenticateAPIKey(c *gin.Context, apiKeyUC usecase.APIKeyUseCase, rawKey string) {
+	key, user, err := apiKeyUC.Authenticate(c.Request.Context(), rawKey)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidAPIKey) {
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error: "Недействительный или отозванный API-ключ",
+				Code:  "INVALID_API_KEY",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: "Внутренняя ошибка сервера",
+			})
+		}
+		c.Abort()
+		return
+	}
+
+	setClaims(c, &utils.Claims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   string(user.Role),
+	})
+	c.Set("apiKey", key)
+	c.Next()
+}
+
+// setClaims сохраняет claims в контексте для использования в handlers
+func setClaims(c *gin.Context, claims *utils.Claims) {
+	c.Set("claims", claims)
+	c.Set("userID", claims.UserID)
+	c.Set("userEmail", claims.Email)
+	c.Set("userRole", claims.Role)
+}
+
+// RequireScope создает middleware, проверяющий права API-ключа.
+// Запросы с JWT сессией пропускаются без ограничений. Должен подключаться после Auth
+func RequireScope(scope entity.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := apiKeyFromContext(c); ok && !key.HasScope(scope) {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error: "API-ключу не выдано право " + string(scope),
+				Code:  "INSUFFICIENT_SCOPE",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SessionOnly создает middleware, запрещающий доступ по API-ключу.
+// Используется для управления аккаунтом и административных маршрутов
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := apiKeyFromContext(c); ok {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error: "Операция недоступна по API-ключу",
+				Code:  "SESSION_REQUIRED",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// apiKeyFromContext возвращает API-ключ, которым аутентифицирован запрос
+func apiKeyFromContext(c *gin.Context) (*entity.APIKey, bool) {
+	value, exists := c.Get("apiKey")
+	if !exists {
+		return nil, false
+	}
+	key, ok := value.(*entity.APIKey)
+	return key, ok
+}
+
+// RequireRole создает middleware, пропускающий только пользователей с одной из указанных ролей.
+// Должен подключаться после Auth
+func RequireRole(roles ...entity.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := entity.RoleUser
+		if claims, exists := c.Get("claims"); exists {
+			if jwtClaims, ok := claims.(*utils.Claims); ok && jwtClaims.Role != "" {
+				role = entity.UserRole(jwtClaims.Role)
+			}
+		}
+
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error: "Недостаточно прав",
+			Code:  "FORBIDDEN",
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/dto"
+	"github.com/redis/go-redis/v9"
+)
+
+// passwordAttemptsScript засчитывает попытку сразу в счетчиках IP и ссылки. Срок жизни ставится
+// в том же скрипте, если его еще нет: ключ без TTL навсегда заблокировал бы ввод пароля
+var passwordAttemptsScript = redis.NewScript(`
+local counts = {}
+for i, key in ipairs(KEYS) do
+	counts[i] = redis.call('INCR', key)
+	if redis.call('TTL', key) < 0 then
+		redis.call('EXPIRE', key, ARGV[1])
+	end
+end
+return counts
+`)
+
+// PasswordAttemptLimiter создает middleware, ограничивающий попытки ввода пароля ссылки.
+// Попытка засчитывается до вызова обработчика, поэтому параллельные запросы не обходят лимит.
+// Счетчиков два: по короткому коду и IP (maxAttempts, успешный переход его сбрасывает)
+// и по одному короткому коду (maxLinkAttempts), который ограничивает перебор с разных адресов
+func PasswordAttemptLimiter(redisClient *redis.Client, maxAttempts, maxLinkAttempts int, windowMinutes int) gin.HandlerFunc {
+	if redisClient == nil {
+		return SimplePasswordAttemptLimiter(maxAttempts, maxLinkAttempts, windowMinutes)
+	}
+
+	window := time.Duration(windowMinutes) * time.Minute
+
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		// Хеш-тег держит оба ключа в одном слоте Redis Cluster
+		linkKey := fmt.Sprintf("link_password_attempts:{%s}", c.Param("code"))
+		ipKey := fmt.Sprintf("%s:%s", linkKey, c.ClientIP())
+
+		counts, err := passwordAttemptsScript.Run(ctx, redisClient, []string{ipKey, linkKey}, int(window.Seconds())).Int64Slice()
+		if err != nil || len(counts) != 2 {
+			// При ошибке Redis пропускаем запрос
+			c.Next()
+			return
+		}
+
+		if counts[0] > int64(maxAttempts) || counts[1] > int64(maxLinkAttempts) {
+			abortTooManyAttempts(c)
+			return
+		}
+
+		c.Next()
+
+		if passwordAccepted(c) {
+			redisClient.Del(ctx, ipKey)
+		}
+	}
+}
+
+// SimplePasswordAttemptLimiter создает in-memory вариант PasswordAttemptLimiter для случаев когда Redis недоступен
+func SimplePasswordAttemptLimiter(maxAttempts, maxLinkAttempts int, windowMinutes int) gin.HandlerFunc {
+	type attemptInfo struct {
+		count     int
+		resetTime time.Time
+	}
+
+	var mu sync.Mutex
+	attempts := make(map[string]*attemptInfo)
+	window := time.Duration(windowMinutes) * time.Minute
+
+	// Периодическая очистка старых записей
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			now := time.Now()
+			mu.Lock()
+			for key, info := range attempts {
+				if now.After(info.resetTime) {
+					delete(attempts, key)
+				}
+			}
+			mu.Unlock()
+		}
+	}()
+
+	// count увеличивает счетчик ключа, начиная новое окно после истечения старого
+	count := func(key string, now time.Time) int {
+		info, ok := attempts[key]
+		if !ok || now.After(info.resetTime) {
+			info = &attemptInfo{resetTime: now.Add(window)}
+			attempts[key] = info
+		}
+		info.count++
+		return info.count
+	}
+
+	return func(c *gin.Context) {
+		linkKey := c.Param("code")
+		ipKey := linkKey + ":" + c.ClientIP()
+
+		mu.Lock()
+		now := time.Now()
+		ipAttempts := count(ipKey, now)
+		linkAttempts := count(linkKey, now)
+		mu.Unlock()
+
+		if ipAttempts > maxAttempts || linkAttempts > maxLinkAttempts {
+			abortTooManyAttempts(c)
+			return
+		}
+
+		c.Next()
+
+		if passwordAccepted(c) {
+			mu.Lock()
+			delete(attempts, ipKey)
+			mu.Unlock()
+		}
+	}
+}
+
+// passwordAccepted сообщает, что обработчик пропустил посетителя по ссылке: после верного пароля
+// отвечают 303, а переходы на резервный адрес (302) счетчик не сбрасывают
+func passwordAccepted(c *gin.Context) bool {
+	return c.Writer.Status() == http.StatusSeeOther
+}
+
+func abortTooManyAttempts(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{
+		Error: "Too many wrong password attempts. Try again later",
+		Code:  "TOO_MANY_PASSWORD_ATTEMPTS",
+	})
+}
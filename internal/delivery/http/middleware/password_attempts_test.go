@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPasswordRouter отвечает 303 на пароль "secret" и 401 на любой другой
+func newPasswordRouter(limiter gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/:code", limiter, func(c *gin.Context) {
+		if c.PostForm("password") == "secret" {
+			c.Status(http.StatusSeeOther)
+			return
+		}
+		c.Status(http.StatusUnauthorized)
+	})
+	return router
+}
+
+func postPassword(router *gin.Engine, code, ip, password string) int {
+	form := url.Values{"password": {password}}
+	req := httptest.NewRequest(http.MethodPost, "/"+code, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func newRedisPasswordLimiter(t *testing.T, maxAttempts, maxLinkAttempts int) (gin.HandlerFunc, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return PasswordAttemptLimiter(client, maxAttempts, maxLinkAttempts, 15), server
+}
+
+func TestPasswordAttemptLimiter(t *testing.T) {
+	limiters := map[string]func(t *testing.T, maxAttempts, maxLinkAttempts int) gin.HandlerFunc{
+		"redis": func(t *testing.T, maxAttempts, maxLinkAttempts int) gin.HandlerFunc {
+			limiter, _ := newRedisPasswordLimiter(t, maxAttempts, maxLinkAttempts)
+			return limiter
+		},
+		"memory": func(t *testing.T, maxAttempts, maxLinkAttempts int) gin.HandlerFunc {
+			return SimplePasswordAttemptLimiter(maxAttempts, maxLinkAttempts, 15)
+		},
+	}
+
+	for name, newLimiter := range limiters {
+		t.Run(name+"/Error - Blocks an IP after too many attempts", func(t *testing.T) {
+			router := newPasswordRouter(newLimiter(t, 2, 100))
+
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.1", "wrong"))
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.1", "wrong"))
+			assert.Equal(t, http.StatusTooManyRequests, postPassword(router, "abc", "10.0.0.1", "secret"))
+			// Другие ссылки и адреса не затронуты
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "other", "10.0.0.1", "wrong"))
+			assert.Equal(t, http.StatusSeeOther, postPassword(router, "abc", "10.0.0.2", "secret"))
+		})
+
+		t.Run(name+"/Error - Blocks a link after attempts from many IPs", func(t *testing.T) {
+			router := newPasswordRouter(newLimiter(t, 2, 3))
+
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.1", "wrong"))
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.2", "wrong"))
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.3", "wrong"))
+			assert.Equal(t, http.StatusTooManyRequests, postPassword(router, "abc", "10.0.0.4", "wrong"))
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "other", "10.0.0.4", "wrong"))
+		})
+
+		t.Run(name+"/Success - Correct password resets the IP counter", func(t *testing.T) {
+			router := newPasswordRouter(newLimiter(t, 2, 100))
+
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.1", "wrong"))
+			assert.Equal(t, http.StatusSeeOther, postPassword(router, "abc", "10.0.0.1", "secret"))
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.1", "wrong"))
+			assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.1", "wrong"))
+		})
+	}
+}
+
+func TestPasswordAttemptLimiter_CountersExpire(t *testing.T) {
+	limiter, server := newRedisPasswordLimiter(t, 1, 100)
+	router := newPasswordRouter(limiter)
+
+	// Ключ, оставшийся без срока жизни, получает его при следующей попытке
+	server.Set("link_password_attempts:{abc}:10.0.0.1", "5")
+
+	assert.Equal(t, http.StatusTooManyRequests, postPassword(router, "abc", "10.0.0.1", "wrong"))
+	assert.Equal(t, 15*time.Minute, server.TTL("link_password_attempts:{abc}:10.0.0.1"))
+	assert.Equal(t, 15*time.Minute, server.TTL("link_password_attempts:{abc}"))
+
+	server.FastForward(15 * time.Minute)
+	assert.Equal(t, http.StatusUnauthorized, postPassword(router, "abc", "10.0.0.1", "wrong"))
+}
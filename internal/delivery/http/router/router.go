@@ -2,6 +2,7 @@ package router
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -10,27 +11,73 @@ import (
 
 	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/handler"
 	"github.com/raison-collab/LinkShorternetBackend/internal/delivery/http/middleware"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
 	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/config"
+	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/geoip"
 	"github.com/raison-collab/LinkShorternetBackend/internal/infrastructure/repository"
 	"github.com/raison-collab/LinkShorternetBackend/internal/usecase"
 	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/useragent"
 )
 
 // NewRouter creates and configures a new router
-func NewRouter(db *sql.DB, redisClient *redis.Client, cfg *config.Config, log logger.Logger) *gin.Engine {
+func NewRouter(db *sql.DB, redisClient *redis.Client, clickQueue usecase.ClickQueue, cfg *config.Config, log logger.Logger) *gin.Engine {
 	// Create repositories
 	userRepo := repository.NewUserRepository(db)
 	linkRepo := repository.NewLinkRepository(db)
+	if redisClient != nil && cfg.Cache.Enabled {
+		linkRepo = repository.NewCachedLinkRepository(
+			linkRepo,
+			redisClient,
+			time.Duration(cfg.Cache.LinkTTLMinutes)*time.Minute,
+			time.Duration(cfg.Cache.NegativeTTLSeconds)*time.Second,
+		)
+	}
 	linkClickRepo := repository.NewLinkClickRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	folderRepo := repository.NewFolderRepository(db)
+	tagRepo := repository.NewTagRepository(db)
 
 	// Create use cases
-	userUC := usecase.NewUserUseCase(userRepo, cfg.JWT.Secret, cfg.JWT.ExpireHours)
-	linkUC := usecase.NewLinkUseCase(linkRepo, linkClickRepo, cfg.URL.ShortURLLength, cfg.URL.BaseURL)
+	userUC := usecase.NewUserUseCase(
+		userRepo,
+		refreshTokenRepo,
+		cfg.JWT.Secret,
+		time.Duration(cfg.JWT.AccessExpireMinutes)*time.Minute,
+		time.Duration(cfg.JWT.RefreshExpireHours)*time.Hour,
+	)
+	ipHashSalt := cfg.Clicks.IPHashSalt
+	if ipHashSalt == "" {
+		ipHashSalt = cfg.JWT.Secret
+	}
+	botDetector := useragent.NewBotDetector(cfg.Bots.ExtraSignatures, cfg.Bots.IgnoreSignatures)
+	clickEnrichers := []usecase.ClickEnricher{usecase.NewUserAgentEnricher(botDetector)}
+	if cfg.GeoIP.DBPath != "" {
+		geoResolver, err := geoip.NewResolver(cfg.GeoIP.DBPath)
+		if err != nil {
+			// Без базы клики пишутся без страны и города, редиректы продолжают работать
+			log.Warnf("GeoIP disabled: %v", err)
+		} else {
+			clickEnrichers = append(clickEnrichers, usecase.NewGeoEnricher(geoResolver))
+		}
+	}
+	linkUC := usecase.NewLinkUseCase(linkRepo, linkClickRepo, clickQueue, cfg.URL.ShortURLLength, cfg.URL.BaseURL, ipHashSalt, clickEnrichers...)
+	adminUC := usecase.NewAdminUseCase(userRepo, linkRepo)
+	apiKeyUC := usecase.NewAPIKeyUseCase(apiKeyRepo, userRepo)
+	dashboardUC := usecase.NewDashboardUseCase(userRepo, linkRepo, linkClickRepo)
+	folderUC := usecase.NewFolderUseCase(folderRepo, linkRepo)
+	tagUC := usecase.NewTagUseCase(tagRepo, linkRepo)
 
 	// Create handlers
 	authHandler := handler.NewAuthHandler(userUC, log)
-	linkHandler := handler.NewLinkHandler(linkUC, log, cfg)
+	linkHandler := handler.NewLinkHandler(linkUC, tagUC, log, cfg)
 	userHandler := handler.NewUserHandler(userUC, log)
+	adminHandler := handler.NewAdminHandler(adminUC, log, cfg)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUC, log)
+	dashboardHandler := handler.NewDashboardHandler(dashboardUC, log, cfg)
+	folderHandler := handler.NewFolderHandler(folderUC, log, cfg)
+	tagHandler := handler.NewTagHandler(tagUC, log, cfg)
 
 	// Create Gin router
 	router := gin.New()
@@ -52,8 +99,13 @@ func NewRouter(db *sql.DB, redisClient *redis.Client, cfg *config.Config, log lo
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Ввод пароля защищенной ссылки ограничивается отдельно от общего rate limit
+	passwordAttempts := middleware.PasswordAttemptLimiter(redisClient,
+		cfg.RateLimit.PasswordAttempts, cfg.RateLimit.PasswordLinkAttempts, cfg.RateLimit.PasswordWindowMinutes)
+
 	// Short URL redirect (must be before API routes)
 	router.GET("/:code", linkHandler.RedirectShortURL)
+	router.POST("/:code", passwordAttempts, linkHandler.UnlockShortURL)
 
 	// API routes
 	api := router.Group("/api/v1")
@@ -63,35 +115,88 @@ func NewRouter(db *sql.DB, redisClient *redis.Client, cfg *config.Config, log lo
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authHandler.Logout)
 		}
 
 		// Protected routes
 		protected := api.Group("")
-		protected.Use(middleware.Auth(cfg.JWT.Secret))
+		protected.Use(middleware.Auth(cfg.JWT.Secret, userUC, apiKeyUC))
 		{
-			// User routes
+			// User routes (account management is not available via API keys)
 			users := protected.Group("/users")
+			users.Use(middleware.SessionOnly())
 			{
 				users.GET("/me", userHandler.GetProfile)
 				users.PUT("/me", userHandler.UpdateProfile)
 				users.PUT("/me/password", userHandler.ChangePassword)
 				users.GET("/me/stats", userHandler.GetStats)
+				users.GET("/me/dashboard", dashboardHandler.GetDashboard)
+				users.GET("/me/api-keys", apiKeyHandler.ListAPIKeys)
+				users.POST("/me/api-keys", apiKeyHandler.CreateAPIKey)
+				users.DELETE("/me/api-keys/:id", apiKeyHandler.RevokeAPIKey)
 			}
 
 			// Link routes
+			linksRead := middleware.RequireScope(entity.ScopeLinksRead)
+			linksWrite := middleware.RequireScope(entity.ScopeLinksWrite)
+			statsRead := middleware.RequireScope(entity.ScopeStatsRead)
+
 			links := protected.Group("/links")
 			{
-				links.POST("", linkHandler.CreateLink)
-				links.GET("", linkHandler.GetUserLinks)
-				links.GET("/:id", linkHandler.GetLink)
-				links.PUT("/:id", linkHandler.UpdateLink)
-				links.DELETE("/:id", linkHandler.DeleteLink)
-				links.GET("/:id/stats", linkHandler.GetLinkStats)
+				links.POST("", linksWrite, linkHandler.CreateLink)
+				links.POST("/batch", linksWrite, linkHandler.CreateLinksBatch)
+				links.GET("/export", linksRead, linkHandler.ExportLinks)
+				links.POST("/import", linksWrite, linkHandler.ImportLinks)
+				links.GET("", linksRead, linkHandler.GetUserLinks)
+				links.GET("/trash", linksRead, linkHandler.GetTrash)
+				links.GET("/:id", linksRead, linkHandler.GetLink)
+				links.PUT("/:id", linksWrite, linkHandler.UpdateLink)
+				links.PATCH("/:id", linksWrite, linkHandler.PatchLink)
+				links.DELETE("/:id", linksWrite, linkHandler.DeleteLink)
+				links.POST("/:id/restore", linksWrite, linkHandler.RestoreLink)
+				links.POST("/:id/activate", linksWrite, linkHandler.ActivateLink)
+				links.POST("/:id/deactivate", linksWrite, linkHandler.DeactivateLink)
+				links.GET("/:id/stats", statsRead, linkHandler.GetLinkStats)
+				links.GET("/:id/clicks", statsRead, linkHandler.ListClicks)
+				links.PUT("/:id/folder", linksWrite, folderHandler.MoveLink)
+				links.PUT("/:id/tags", linksWrite, tagHandler.SetLinkTags)
+			}
+
+			// Folder and tag routes
+			folders := protected.Group("/folders")
+			{
+				folders.GET("", linksRead, folderHandler.ListFolders)
+				folders.POST("", linksWrite, folderHandler.CreateFolder)
+				folders.PUT("/:id", linksWrite, folderHandler.RenameFolder)
+				folders.DELETE("/:id", linksWrite, folderHandler.DeleteFolder)
+			}
+
+			tags := protected.Group("/tags")
+			{
+				tags.GET("", linksRead, tagHandler.ListTags)
+				tags.POST("", linksWrite, tagHandler.CreateTag)
+				tags.PUT("/:id", linksWrite, tagHandler.RenameTag)
+				tags.DELETE("/:id", linksWrite, tagHandler.DeleteTag)
+			}
+
+			// Admin routes
+			admin := protected.Group("/admin")
+			admin.Use(middleware.SessionOnly(), middleware.RequireRole(entity.RoleAdmin))
+			{
+				admin.GET("/users", adminHandler.ListUsers)
+				admin.PUT("/users/:id/role", adminHandler.SetUserRole)
+				admin.GET("/links", adminHandler.ListLinks)
+				admin.POST("/links/:id/disable", adminHandler.DisableLink)
+				admin.POST("/links/:id/enable", adminHandler.EnableLink)
+				admin.GET("/stats", adminHandler.GetGlobalStats)
+				admin.GET("/metrics", adminHandler.GetMetrics)
 			}
 		}
 
 		// Public redirect inside API prefix (optional convenience)
 		api.GET("/:code", linkHandler.RedirectShortURL)
+		api.POST("/:code", passwordAttempts, linkHandler.UnlockShortURL)
 	}
 
 	return router
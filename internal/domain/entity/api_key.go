@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+)
+
+// APIKey represents a personal API key used for programmatic access
+type APIKey struct {
+	ID         int64         `json:"id" db:"id"`
+	UserID     int64         `json:"user_id" db:"user_id"`
+	Name       string        `json:"name" db:"name"`
+	Prefix     string        `json:"prefix" db:"key_prefix"`
+	KeyHash    string        `json:"-" db:"key_hash"`
+	Scopes     []APIKeyScope `json:"scopes" db:"scopes"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+// APIKeyScope represents a permission granted to an API key
+type APIKeyScope string
+
+const (
+	ScopeLinksRead  APIKeyScope = "links:read"
+	ScopeLinksWrite APIKeyScope = "links:write"
+	ScopeStatsRead  APIKeyScope = "stats:read"
+)
+
+// IsValid проверяет, что scope известен системе
+func (s APIKeyScope) IsValid() bool {
+	return s == ScopeLinksRead || s == ScopeLinksWrite || s == ScopeStatsRead
+}
+
+// HasScope проверяет, что ключу выдан указанный scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -6,41 +6,199 @@ import (
 
 // Link represents a shortened URL entity
 type Link struct {
-	ID          int64      `json:"id" db:"id"`
-	ShortCode   string     `json:"short_code" db:"short_code"`
-	OriginalURL string     `json:"original_url" db:"original_url"`
-	UserID      *int64     `json:"user_id,omitempty" db:"user_id"`
-	Clicks      int64      `json:"clicks" db:"clicks"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID                  int64      `json:"id" db:"id"`
+	ShortCode           string     `json:"short_code" db:"short_code"`
+	OriginalURL         string     `json:"original_url" db:"original_url"`
+	Title               string     `json:"title,omitempty" db:"title"`
+	UserID              *int64     `json:"user_id,omitempty" db:"user_id"`
+	Clicks              int64      `json:"clicks" db:"clicks"`
+	IsActive            bool       `json:"is_active" db:"is_active"`
+	DisabledByAdmin     bool       `json:"disabled_by_admin" db:"disabled_by_admin"` // moderation flag the owner cannot clear
+	ExpiresAt           *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	StartsAt            *time.Time `json:"starts_at,omitempty" db:"starts_at"` // nil means the link is live immediately
+	UTM                 UTM        `json:"utm"`
+	FolderID            *int64     `json:"folder_id,omitempty" db:"folder_id"`
+	Tags                []string   `json:"tags"`                                 // tag names, sorted
+	PasswordHash        string     `json:"-" db:"password_hash"`                 // bcrypt hash; empty if the link is not protected
+	MaxClicks           *int64     `json:"max_clicks,omitempty" db:"max_clicks"` // nil means unlimited
+	Uses                int64      `json:"uses" db:"uses"`                       // redirects consumed against MaxClicks
+	DeleteWhenExhausted bool       `json:"delete_when_exhausted" db:"delete_when_exhausted"`
+	FallbackURL         string     `json:"fallback_url,omitempty" db:"fallback_url"` // where to send visitors once the link can't be opened
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt           *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // set while the link is in the trash
+}
+
+// IsExhausted reports whether the link has used up its click limit
+func (l *Link) IsExhausted() bool {
+	return l.MaxClicks != nil && l.Uses >= *l.MaxClicks
+}
+
+// IsPasswordProtected reports whether following the link requires a password
+func (l *Link) IsPasswordProtected() bool {
+	return l.PasswordHash != ""
+}
+
+// UTM holds the utm_* query parameters of a link's destination.
+// They are stored next to the URL so clicks can be grouped by campaign
+type UTM struct {
+	Source   string `json:"source,omitempty" db:"utm_source"`
+	Medium   string `json:"medium,omitempty" db:"utm_medium"`
+	Campaign string `json:"campaign,omitempty" db:"utm_campaign"`
+	Term     string `json:"term,omitempty" db:"utm_term"`
+	Content  string `json:"content,omitempty" db:"utm_content"`
+}
+
+// IsZero reports whether no UTM parameter is set
+func (u UTM) IsZero() bool {
+	return u == UTM{}
+}
+
+// LinkFilter represents filter parameters for listing links
+type LinkFilter struct {
+	UserID      *int64
+	Search      string // words of the title, short code or original URL, or a substring of the code or URL
+	IsActive    *bool
+	Expired     *bool
+	CreatedFrom *time.Time // inclusive
+	CreatedTo   *time.Time // exclusive
+	MinClicks   *int64
+	Tag         string // tag name
+	FolderID    *int64
+	Deleted     bool     // list links in the trash instead of live ones
+	Sort        LinkSort // defaults to LinkSortCreated
+	Ascending   bool     // sort order; descending by default
+	Offset      int
+	Limit       int
+}
+
+// LinkSort is the field a link list is ordered by
+type LinkSort string
+
+const (
+	LinkSortCreated LinkSort = "created"
+	LinkSortUpdated LinkSort = "updated"
+	LinkSortClicks  LinkSort = "clicks"
+	// LinkSortDeleted orders the trash; it is not accepted from clients
+	LinkSortDeleted LinkSort = "deleted"
+)
+
+// IsValid reports whether the sort field is supported
+func (s LinkSort) IsValid() bool {
+	switch s {
+	case LinkSortCreated, LinkSortUpdated, LinkSortClicks:
+		return true
+	default:
+		return false
+	}
 }
 
 // LinkClick represents a click event on a shortened link
 type LinkClick struct {
-	ID        int64     `json:"id" db:"id"`
-	LinkID    int64     `json:"link_id" db:"link_id"`
-	IPAddress string    `json:"ip_address" db:"ip_address"`
-	UserAgent string    `json:"user_agent" db:"user_agent"`
-	Referer   string    `json:"referer,omitempty" db:"referer"`
-	Country   string    `json:"country,omitempty" db:"country"`
-	City      string    `json:"city,omitempty" db:"city"`
-	ClickedAt time.Time `json:"clicked_at" db:"clicked_at"`
+	ID         int64     `json:"id" db:"id"`
+	LinkID     int64     `json:"link_id" db:"link_id"`
+	IPAddress  string    `json:"ip_address" db:"ip_address"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	Referer    string    `json:"referer,omitempty" db:"referer"`
+	Country    string    `json:"country,omitempty" db:"country"`
+	City       string    `json:"city,omitempty" db:"city"`
+	DeviceType string    `json:"device_type,omitempty" db:"device_type"`
+	OS         string    `json:"os,omitempty" db:"os"`
+	Browser    string    `json:"browser,omitempty" db:"browser"`
+	IsBot      bool      `json:"is_bot" db:"is_bot"`
+	ClickedAt  time.Time `json:"clicked_at" db:"clicked_at"`
+}
+
+// GeoLocation is the location resolved from a visitor's IP address
+type GeoLocation struct {
+	Country string // ISO 3166-1 alpha-2 code
+	City    string
+}
+
+// ClickCursor is a keyset position in the click log, ordered by (ClickedAt, ID) descending
+type ClickCursor struct {
+	ClickedAt time.Time
+	ID        int64
+}
+
+// ClickFilter represents filter parameters for reading the click log
+type ClickFilter struct {
+	LinkID int64
+	From   *time.Time   // inclusive
+	To     *time.Time   // exclusive
+	After  *ClickCursor // return clicks strictly older than the cursor
+	Limit  int
+}
+
+// Granularity is the width of a bucket in the click timeline
+type Granularity string
+
+const (
+	GranularityHour  Granularity = "hour"
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week" // weeks start on Monday
+	GranularityMonth Granularity = "month"
+)
+
+// IsValid reports whether the granularity is supported
+func (g Granularity) IsValid() bool {
+	switch g {
+	case GranularityHour, GranularityDay, GranularityWeek, GranularityMonth:
+		return true
+	}
+	return false
+}
+
+// StatsFilter represents filter parameters for link statistics
+type StatsFilter struct {
+	LinkID      int64
+	UserID      int64 // used when LinkID is zero: statistics across all links of the user
+	From        time.Time
+	To          time.Time
+	IncludeBots bool           // count clicks flagged as bots in all figures
+	Location    *time.Location // timeline hours and dates are computed in this time zone
+}
+
+// TimeBucket is the number of clicks in one timeline bucket
+type TimeBucket struct {
+	Start  time.Time `json:"start"`
+	Clicks int64     `json:"clicks"`
 }
 
 // LinkStats represents statistics for a link
 type LinkStats struct {
-	LinkID       int64                  `json:"link_id"`
-	TotalClicks  int64                  `json:"total_clicks"`
-	UniqueClicks int64                  `json:"unique_clicks"`
-	ClicksByDate map[string]int64       `json:"clicks_by_date"`
-	ClicksByCountry map[string]int64    `json:"clicks_by_country"`
-	ClicksByDevice map[string]int64     `json:"clicks_by_device"`
-	TopReferers  []RefererStats         `json:"top_referers"`
+	LinkID            int64            `json:"link_id"`
+	TotalClicks       int64            `json:"total_clicks"`
+	UniqueClicks      int64            `json:"unique_clicks"`
+	UniqueClicksSince *time.Time       `json:"unique_clicks_since,omitempty"` // set when UniqueClicks covers only the last 90 days of a longer period
+	BotClicks         int64            `json:"bot_clicks"`                    // reported regardless of IncludeBots
+	ClicksByDate      map[string]int64 `json:"clicks_by_date"`
+	Granularity       Granularity      `json:"granularity"`
+	Timezone          string           `json:"timezone"`
+	Timeline          []TimeBucket     `json:"timeline"` // ordered by Start
+	ClicksByCountry   map[string]int64 `json:"clicks_by_country"`
+	ClicksByDevice    map[string]int64 `json:"clicks_by_device"`
+	ClicksByOS        map[string]int64 `json:"clicks_by_os"`
+	ClicksByBrowser   map[string]int64 `json:"clicks_by_browser"`
+	ClicksByCampaign  map[string]int64 `json:"clicks_by_campaign"` // keyed by utm_campaign, "None" for untagged links
+	TopReferers       []RefererStats   `json:"top_referers"`
+	TopLinks          []LinkClickCount `json:"top_links"` // at most 10, by clicks
+}
+
+// ClickTotals holds the headline figures of LinkStats
+type ClickTotals struct {
+	TotalClicks  int64 `json:"total_clicks"`
+	UniqueClicks int64 `json:"unique_clicks"`
+}
+
+// LinkClickCount is the number of clicks of one link
+type LinkClickCount struct {
+	LinkID int64 `json:"link_id"`
+	Clicks int64 `json:"clicks"`
 }
 
 // RefererStats represents referrer statistics
 type RefererStats struct {
 	Referer string `json:"referer"`
 	Count   int64  `json:"count"`
-} 
\ No newline at end of file
+}
@@ -9,10 +9,23 @@ type User struct {
 	ID           int64     `json:"id" db:"id"`
 	Email        string    `json:"email" db:"email"`
 	PasswordHash string    `json:"-" db:"password_hash"`
+	Role         UserRole  `json:"role" db:"role"`
+	TokenVersion int       `json:"-" db:"token_version"`
+	FallbackURL  string    `json:"fallback_url,omitempty" db:"fallback_url"` // used for the user's links without their own fallback
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// RefreshToken represents a server-side refresh token session
+type RefreshToken struct {
+	ID        int64      `json:"id" db:"id"`
+	UserID    int64      `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
 // UserRole represents user roles
 type UserRole string
 
@@ -21,6 +34,11 @@ const (
 	RoleAdmin UserRole = "admin"
 )
 
+// IsValid проверяет, что роль известна системе
+func (r UserRole) IsValid() bool {
+	return r == RoleUser || r == RoleAdmin
+}
+
 // UserStats represents user statistics
 type UserStats struct {
 	UserID      int64 `json:"user_id"`
@@ -28,3 +46,51 @@ type UserStats struct {
 	TotalClicks int64 `json:"total_clicks"`
 	ActiveLinks int64 `json:"active_links"`
 }
+
+// Dashboard represents account-wide analytics for a period
+type Dashboard struct {
+	From         time.Time        `json:"from"`
+	To           time.Time        `json:"to"`
+	Granularity  Granularity      `json:"granularity"`
+	Timezone     string           `json:"timezone"`
+	TotalLinks   int64            `json:"total_links"`
+	ActiveLinks  int64            `json:"active_links"`
+	Clicks       PeriodComparison `json:"clicks"`
+	UniqueClicks PeriodComparison `json:"unique_clicks"`
+	Timeline     []TimeBucket     `json:"timeline"`
+	TopLinks     []TopLink        `json:"top_links"`
+	TopCampaigns map[string]int64 `json:"top_campaigns"`
+	TopCountries map[string]int64 `json:"top_countries"`
+	TopReferers  []RefererStats   `json:"top_referers"`
+}
+
+// PeriodComparison compares a figure with the previous period of the same length
+type PeriodComparison struct {
+	Current       int64    `json:"current"`
+	Previous      int64    `json:"previous"`
+	GrowthPercent *float64 `json:"growth_percent"` // nil when the previous period has no clicks
+}
+
+// TopLink is a link with its clicks in the period
+type TopLink struct {
+	Link   *Link `json:"link"`
+	Clicks int64 `json:"clicks"`
+}
+
+// UserFilter represents filter parameters for listing users
+type UserFilter struct {
+	Search string // substring of email
+	Role   UserRole
+	Offset int
+	Limit  int
+}
+
+// GlobalStats represents service-wide statistics
+type GlobalStats struct {
+	TotalUsers    int64 `json:"total_users"`
+	TotalAdmins   int64 `json:"total_admins"`
+	TotalLinks    int64 `json:"total_links"`
+	ActiveLinks   int64 `json:"active_links"`
+	TotalClicks   int64 `json:"total_clicks"`
+	ClicksLast24h int64 `json:"clicks_last_24h"`
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+)
+
+// APIKeyRepository defines methods for API key data access
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// GetByHash retrieves an API key by the hash of its secret
+	GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// GetByUserID retrieves all API keys of a user, including revoked ones
+	GetByUserID(ctx context.Context, userID int64) ([]*entity.APIKey, error)
+
+	// Revoke revokes a user's key; returns false if no active key matched
+	Revoke(ctx context.Context, id, userID int64) (bool, error)
+
+	// UpdateLastUsed records the time the key was last used
+	UpdateLastUsed(ctx context.Context, id int64, usedAt time.Time) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// ClickRollupRepository defines methods for maintaining pre-aggregated click tables
+type ClickRollupRepository interface {
+	// RollUp aggregates raw clicks of whole UTC hours from the current watermark up to until,
+	// at most maxHours hours per call, and returns the new watermark. Hours below the watermark
+	// that received clicks after they were aggregated are rebuilt in the same call.
+	// Concurrent calls from several instances are serialized by the database
+	RollUp(ctx context.Context, until time.Time, maxHours int) (time.Time, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+)
+
+// FolderRepository defines methods for folder data access
+type FolderRepository interface {
+	// Create creates a new folder
+	Create(ctx context.Context, folder *entity.Folder) error
+
+	// GetByID retrieves a folder by its ID
+	GetByID(ctx context.Context, id int64) (*entity.Folder, error)
+
+	// GetByName retrieves a user's folder by its name
+	GetByName(ctx context.Context, userID int64, name string) (*entity.Folder, error)
+
+	// GetByUserID retrieves all folders of a user, ordered by name
+	GetByUserID(ctx context.Context, userID int64) ([]*entity.Folder, error)
+
+	// Update renames a folder
+	Update(ctx context.Context, folder *entity.Folder) error
+
+	// Delete deletes a folder; its links are kept and no longer belong to a folder
+	Delete(ctx context.Context, id int64) error
+}
@@ -12,32 +12,73 @@ type LinkRepository interface {
 	// Create creates a new link
 	Create(ctx context.Context, link *entity.Link) error
 
-	// GetByShortCode retrieves a link by its short code
+	// CreateBatch creates several links in a single transaction: either all or none are stored
+	CreateBatch(ctx context.Context, links []*entity.Link) error
+
+	// GetByShortCode retrieves a link by its short code; links in the trash are not returned
 	GetByShortCode(ctx context.Context, shortCode string) (*entity.Link, error)
 
-	// GetByID retrieves a link by its ID
+	// GetByID retrieves a link by its ID; links in the trash are not returned
 	GetByID(ctx context.Context, id int64) (*entity.Link, error)
 
+	// GetByIDs retrieves the links with the given IDs in any order; links in the trash and unknown IDs are skipped
+	GetByIDs(ctx context.Context, ids []int64) ([]*entity.Link, error)
+
 	// GetByUserID retrieves all links for a specific user
 	GetByUserID(ctx context.Context, userID int64, offset, limit int) ([]*entity.Link, error)
 
 	// Update updates an existing link
 	Update(ctx context.Context, link *entity.Link) error
 
-	// Delete deletes a link by ID
+	// SetDisabledByAdmin sets or clears the moderation flag of a link. It is stored apart from Update,
+	// so owners saving their links never overwrite it
+	SetDisabledByAdmin(ctx context.Context, id int64, disabled bool) error
+
+	// Delete moves a link to the trash. Its clicks and short code are kept until it is purged
 	Delete(ctx context.Context, id int64) error
 
+	// GetDeletedByID retrieves a link in the trash by its ID
+	GetDeletedByID(ctx context.Context, id int64) (*entity.Link, error)
+
+	// Restore takes a link out of the trash
+	Restore(ctx context.Context, id int64) error
+
+	// PurgeDeleted permanently removes up to limit links moved to the trash before the given time,
+	// together with their clicks. Returns the short codes that were released
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]string, error)
+
 	// IncrementClicks increments the click count for a link
 	IncrementClicks(ctx context.Context, linkID int64) error
 
-	// GetExpiredLinks retrieves all expired links
-	GetExpiredLinks(ctx context.Context, before time.Time) ([]*entity.Link, error)
+	// GetFallbackURL returns the fallback of the link, or of its owner if the link has none;
+	// empty if neither is set or the short code does not exist
+	GetFallbackURL(ctx context.Context, shortCode string) (string, error)
+
+	// ConsumeUse atomically takes one redirect from the link's click limit and returns
+	// the number of uses after it; ok is false if the limit was already reached
+	ConsumeUse(ctx context.Context, linkID int64) (uses int64, ok bool, err error)
+
+	// GetExpiredLinks retrieves up to limit links that expired before the given time, oldest first
+	GetExpiredLinks(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error)
+
+	// PurgeExpired removes the given links if they still expired before the given time, copying them
+	// and their clicks and rollups to the archive tables first when archive is set. Returns the short codes that were released
+	PurgeExpired(ctx context.Context, ids []int64, before time.Time, archive bool) ([]string, error)
 
 	// CountByUserID counts links for a specific user
 	CountByUserID(ctx context.Context, userID int64) (int64, error)
 
 	// ExistsByShortCode checks if a short code already exists
 	ExistsByShortCode(ctx context.Context, shortCode string) (bool, error)
+
+	// FindExistingShortCodes returns the subset of the given short codes that are already taken
+	FindExistingShortCodes(ctx context.Context, shortCodes []string) ([]string, error)
+
+	// List retrieves links matching the filter
+	List(ctx context.Context, filter entity.LinkFilter) ([]*entity.Link, error)
+
+	// Count counts links matching the filter
+	Count(ctx context.Context, filter entity.LinkFilter) (int64, error)
 }
 
 // LinkClickRepository defines methods for link click data access
@@ -45,11 +86,20 @@ type LinkClickRepository interface {
 	// Create records a new click
 	Create(ctx context.Context, click *entity.LinkClick) error
 
-	// GetByLinkID retrieves all clicks for a specific link
-	GetByLinkID(ctx context.Context, linkID int64, offset, limit int) ([]*entity.LinkClick, error)
+	// CreateBatch records several clicks with a single query, skipping clicks of deleted links,
+	// and adds counts (link ID -> delta) to the click counters of the links in the same transaction
+	CreateBatch(ctx context.Context, clicks []*entity.LinkClick, counts map[int64]int64) error
+
+	// GetByLinkID retrieves clicks of a link matching the filter, newest first
+	GetByLinkID(ctx context.Context, filter entity.ClickFilter) ([]*entity.LinkClick, error)
+
+	// GetStats retrieves statistics for a link, or for all links of filter.UserID when filter.LinkID is zero.
+	// Bot clicks are excluded unless filter.IncludeBots is set.
+	// The timeline contains only non-empty hours in filter.Location; callers regroup it as needed
+	GetStats(ctx context.Context, filter entity.StatsFilter) (*entity.LinkStats, error)
 
-	// GetStats retrieves statistics for a link
-	GetStats(ctx context.Context, linkID int64, from, to time.Time) (*entity.LinkStats, error)
+	// GetTotals counts clicks and unique visitors for the same filter as GetStats, without the breakdowns
+	GetTotals(ctx context.Context, filter entity.StatsFilter) (*entity.ClickTotals, error)
 
 	// CountByLinkID counts clicks for a specific link
 	CountByLinkID(ctx context.Context, linkID int64) (int64, error)
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+)
+
+// TagRepository defines methods for tag data access
+type TagRepository interface {
+	// Create creates a new tag
+	Create(ctx context.Context, tag *entity.Tag) error
+
+	// GetByID retrieves a tag by its ID
+	GetByID(ctx context.Context, id int64) (*entity.Tag, error)
+
+	// GetByName retrieves a user's tag by its name
+	GetByName(ctx context.Context, userID int64, name string) (*entity.Tag, error)
+
+	// GetByUserID retrieves all tags of a user, ordered by name
+	GetByUserID(ctx context.Context, userID int64) ([]*entity.Tag, error)
+
+	// Update renames a tag
+	Update(ctx context.Context, tag *entity.Tag) error
+
+	// Delete deletes a tag and removes it from all links
+	Delete(ctx context.Context, id int64) error
+
+	// SetLinkTags replaces the tags of a link with the given names in a single transaction,
+	// creating the user's tags that do not exist yet
+	SetLinkTags(ctx context.Context, linkID, userID int64, names []string) error
+}
@@ -17,9 +17,12 @@ type UserRepository interface {
 	// GetByEmail retrieves a user by email
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 
-	// Update updates user information
+	// Update updates user information except the role
 	Update(ctx context.Context, user *entity.User) error
 
+	// SetRole changes only the role of a user and returns the updated user, or nil if it does not exist
+	SetRole(ctx context.Context, userID int64, role entity.UserRole) (*entity.User, error)
+
 	// Delete deletes a user
 	Delete(ctx context.Context, id int64) error
 
@@ -28,4 +31,31 @@ type UserRepository interface {
 
 	// GetStats retrieves user statistics
 	GetStats(ctx context.Context, userID int64) (*entity.UserStats, error)
+
+	// List retrieves users matching the filter
+	List(ctx context.Context, filter entity.UserFilter) ([]*entity.User, error)
+
+	// Count counts users matching the filter
+	Count(ctx context.Context, filter entity.UserFilter) (int64, error)
+
+	// GetGlobalStats retrieves service-wide statistics
+	GetGlobalStats(ctx context.Context) (*entity.GlobalStats, error)
+
+	// IncrementTokenVersion invalidates all access tokens issued to the user
+	IncrementTokenVersion(ctx context.Context, userID int64) error
+}
+
+// RefreshTokenRepository defines methods for refresh token data access
+type RefreshTokenRepository interface {
+	// Create stores a new refresh token
+	Create(ctx context.Context, token *entity.RefreshToken) error
+
+	// GetByHash retrieves a refresh token by its hash
+	GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+
+	// Revoke revokes a token; returns false if it was already revoked
+	Revoke(ctx context.Context, id int64) (bool, error)
+
+	// RevokeAllByUserID revokes all active tokens of a user
+	RevokeAllByUserID(ctx context.Context, userID int64) error
 }
@@ -0,0 +1,363 @@
+package clickqueue
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+// ErrQueueFull возвращается, когда очередь кликов переполнена и клик отброшен
+var ErrQueueFull = errors.New("click queue is full")
+
+// ErrPipelineClosed возвращается при попытке записать клик после остановки конвейера
+var ErrPipelineClosed = errors.New("click pipeline is closed")
+
+// Config содержит настройки конвейера записи кликов
+type Config struct {
+	QueueSize     int
+	Workers       int
+	BatchSize     int
+	FlushInterval time.Duration
+
+	// Stream включает буферизацию кликов в Redis Stream; пустая строка - только память
+	Stream        string
+	StreamMaxLen  int64
+	ConsumerGroup string
+	ConsumerName  string
+	// ClaimIdle - сколько сообщение может висеть неподтвержденным у другого потребителя группы,
+	// прежде чем его заберут на запись
+	ClaimIdle time.Duration
+}
+
+// Pipeline принимает клики из обработчика редиректа и записывает их в базу
+// фоновыми воркерами пачками: один многострочный INSERT и агрегированное
+// увеличение счетчиков на батч в одной транзакции
+type Pipeline struct {
+	clickRepo repository.LinkClickRepository
+	redis     *redis.Client
+	cfg       Config
+	log       logger.Logger
+
+	queue   chan *entity.LinkClick
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// NewPipeline создает конвейер записи кликов. redisClient нужен только при включенном Stream
+func NewPipeline(clickRepo repository.LinkClickRepository, redisClient *redis.Client, cfg Config, log logger.Logger) *Pipeline {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = 5 * time.Minute
+	}
+	if redisClient == nil {
+		cfg.Stream = ""
+	}
+
+	return &Pipeline{
+		clickRepo: clickRepo,
+		redis:     redisClient,
+		cfg:       cfg,
+		log:       log,
+		queue:     make(chan *entity.LinkClick, cfg.QueueSize),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start запускает фоновые воркеры
+func (p *Pipeline) Start(ctx context.Context) error {
+	if p.cfg.Stream != "" {
+		err := p.redis.XGroupCreateMkStream(ctx, p.cfg.Stream, p.cfg.ConsumerGroup, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("failed to create consumer group: %w", err)
+		}
+
+		for i := 0; i < p.cfg.Workers; i++ {
+			p.wg.Add(1)
+			go p.consumeStream(fmt.Sprintf("%s-%d", p.cfg.ConsumerName, i))
+		}
+	}
+
+	// Воркеры памяти работают и в режиме Stream: туда попадают клики,
+	// которые не удалось отправить в Redis
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.consumeQueue()
+	}
+
+	return nil
+}
+
+// Enqueue ставит клик в очередь без ожидания записи в базу
+func (p *Pipeline) Enqueue(ctx context.Context, click *entity.LinkClick) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPipelineClosed
+	}
+
+	if p.cfg.Stream != "" {
+		payload, err := encodeClick(click)
+		if err == nil {
+			err = p.redis.XAdd(ctx, &redis.XAddArgs{
+				Stream: p.cfg.Stream,
+				MaxLen: p.cfg.StreamMaxLen,
+				Approx: true,
+				Values: map[string]interface{}{"click": payload},
+			}).Err()
+		}
+		if err == nil {
+			return nil
+		}
+		p.log.Warnf("Failed to push click to Redis stream, falling back to memory queue: %v", err)
+	}
+
+	select {
+	case p.queue <- click:
+		return nil
+	default:
+		if n := p.dropped.Add(1); n == 1 || n%1000 == 0 {
+			p.log.Warnf("Click queue is full, %d clicks dropped so far", n)
+		}
+		return ErrQueueFull
+	}
+}
+
+// Shutdown прекращает прием кликов и дожидается записи накопленных батчей
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.stop)
+	close(p.queue)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("click pipeline did not drain in time: %w", ctx.Err())
+	}
+}
+
+// consumeQueue читает клики из памяти и пишет их пачками по размеру или по таймеру
+func (p *Pipeline) consumeQueue() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*entity.LinkClick, 0, p.cfg.BatchSize)
+
+	for {
+		select {
+		case click, ok := <-p.queue:
+			if !ok {
+				// Очередь закрыта и вычитана до конца
+				p.flush(batch)
+				return
+			}
+			batch = append(batch, click)
+			if len(batch) >= p.cfg.BatchSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// consumeStream читает клики из Redis Stream в составе consumer group.
+// Сообщения подтверждаются только после успешной записи, поэтому
+// неподтвержденные после падения клики дочитываются при следующем старте,
+// а зависшие у других потребителей забираются раз в ClaimIdle
+func (p *Pipeline) consumeStream(consumer string) {
+	defer p.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.stop
+		cancel()
+	}()
+
+	// Сначала дочитываем собственные неподтвержденные сообщения, затем новые
+	startID := "0"
+	claimStart := "0-0"
+	nextClaim := time.Now().Add(p.cfg.ClaimIdle)
+
+	for {
+		if startID == ">" && time.Now().After(nextClaim) {
+			nextClaim = time.Now().Add(p.cfg.ClaimIdle)
+			var claimed bool
+			claimStart, claimed = p.claimStale(ctx, consumer, claimStart)
+			if claimed {
+				// Забранные сообщения теперь в собственном pending
+				startID = "0"
+			}
+		}
+
+		streams, err := p.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    p.cfg.ConsumerGroup,
+			Consumer: consumer,
+			Streams:  []string{p.cfg.Stream, startID},
+			Count:    int64(p.cfg.BatchSize),
+			Block:    p.cfg.FlushInterval,
+		}).Result()
+
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if !errors.Is(err, redis.Nil) {
+				p.log.Errorf("Failed to read clicks from Redis stream: %v", err)
+				time.Sleep(p.cfg.FlushInterval)
+			}
+			continue
+		}
+
+		var messages []redis.XMessage
+		for _, stream := range streams {
+			messages = append(messages, stream.Messages...)
+		}
+
+		if len(messages) == 0 && startID == "0" {
+			startID = ">"
+			continue
+		}
+
+		clicks := make([]*entity.LinkClick, 0, len(messages))
+		ids := make([]string, 0, len(messages))
+		for _, msg := range messages {
+			ids = append(ids, msg.ID)
+			payload, _ := msg.Values["click"].(string)
+			click, err := decodeClick(payload)
+			if err != nil {
+				p.log.Errorf("Skipping malformed click message %s: %v", msg.ID, err)
+				continue
+			}
+			clicks = append(clicks, click)
+		}
+
+		if !p.flush(clicks) {
+			// Оставляем сообщения в pending и перечитываем их позже
+			startID = "0"
+			time.Sleep(p.cfg.FlushInterval)
+			continue
+		}
+
+		ackCtx, ackCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := p.redis.XAck(ackCtx, p.cfg.Stream, p.cfg.ConsumerGroup, ids...).Err(); err != nil {
+			p.log.Errorf("Failed to ack click messages: %v", err)
+		} else {
+			p.redis.XDel(ackCtx, p.cfg.Stream, ids...)
+		}
+		ackCancel()
+	}
+}
+
+// claimStale переводит на consumer сообщения, которые другие потребители группы прочитали,
+// но не подтвердили дольше ClaimIdle: экземпляр упал или перезапустился под другим именем.
+// Возвращает курсор для следующего вызова и признак, что что-то забрано
+func (p *Pipeline) claimStale(ctx context.Context, consumer, start string) (string, bool) {
+	messages, next, err := p.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   p.cfg.Stream,
+		Group:    p.cfg.ConsumerGroup,
+		Consumer: consumer,
+		MinIdle:  p.cfg.ClaimIdle,
+		Start:    start,
+		Count:    int64(p.cfg.BatchSize),
+	}).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			p.log.Errorf("Failed to claim stale click messages: %v", err)
+		}
+		return start, false
+	}
+
+	if len(messages) > 0 {
+		p.log.Infof("Claimed %d stale click messages from other consumers", len(messages))
+	}
+	return next, len(messages) > 0
+}
+
+// flush записывает батч кликов и увеличивает счетчики ссылок
+func (p *Pipeline) flush(batch []*entity.LinkClick) bool {
+	if len(batch) == 0 {
+		return true
+	}
+
+	// Контекст не привязан к остановке: начатый батч должен дописаться
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Клики ботов записываются с флагом, но счетчики ссылок не увеличивают
+	deltas := make(map[int64]int64)
+	for _, click := range batch {
+		if !click.IsBot {
+			deltas[click.LinkID]++
+		}
+	}
+
+	if err := p.clickRepo.CreateBatch(ctx, batch, deltas); err != nil {
+		p.log.Errorf("Failed to write batch of %d clicks: %v", len(batch), err)
+		return false
+	}
+
+	return true
+}
+
+func encodeClick(click *entity.LinkClick) (string, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(click); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func decodeClick(payload string) (*entity.LinkClick, error) {
+	var click entity.LinkClick
+	if err := gob.NewDecoder(strings.NewReader(payload)).Decode(&click); err != nil {
+		return nil, err
+	}
+	return &click, nil
+}
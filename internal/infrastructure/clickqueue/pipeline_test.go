@@ -0,0 +1,97 @@
+package clickqueue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClickRepo struct {
+	repository.LinkClickRepository
+	mu      sync.Mutex
+	batches [][]*entity.LinkClick
+	deltas  map[int64]int64
+}
+
+func (r *fakeClickRepo) CreateBatch(ctx context.Context, clicks []*entity.LinkClick, counts map[int64]int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]*entity.LinkClick(nil), clicks...))
+	for id, delta := range counts {
+		r.deltas[id] += delta
+	}
+	return nil
+}
+
+func TestPipeline_BatchesAndDrainsOnShutdown(t *testing.T) {
+	clickRepo := &fakeClickRepo{deltas: make(map[int64]int64)}
+
+	p := NewPipeline(clickRepo, nil, Config{
+		QueueSize:     100,
+		Workers:       1,
+		BatchSize:     4,
+		FlushInterval: time.Hour,
+	}, logger.New("error", "text"))
+	assert.NoError(t, p.Start(context.Background()))
+
+	for i := 0; i < 10; i++ {
+		linkID := int64(1)
+		if i%2 == 1 {
+			linkID = 2
+		}
+		assert.NoError(t, p.Enqueue(context.Background(), &entity.LinkClick{LinkID: linkID}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, p.Shutdown(ctx))
+
+	// 4 + 4 по размеру батча и остаток 2 при остановке
+	assert.Len(t, clickRepo.batches, 3)
+	assert.Len(t, clickRepo.batches[2], 2)
+	assert.Equal(t, map[int64]int64{1: 5, 2: 5}, clickRepo.deltas)
+
+	assert.ErrorIs(t, p.Enqueue(context.Background(), &entity.LinkClick{LinkID: 1}), ErrPipelineClosed)
+}
+
+func TestPipeline_DropsWhenQueueIsFull(t *testing.T) {
+	p := NewPipeline(&fakeClickRepo{deltas: make(map[int64]int64)}, nil, Config{
+		QueueSize: 1,
+		Workers:   1,
+	}, logger.New("error", "text"))
+
+	// Воркеры не запущены, поэтому второй клик не помещается в очередь
+	assert.NoError(t, p.Enqueue(context.Background(), &entity.LinkClick{LinkID: 1}))
+	assert.ErrorIs(t, p.Enqueue(context.Background(), &entity.LinkClick{LinkID: 1}), ErrQueueFull)
+}
+
+func TestPipeline_BotClicksAreNotCounted(t *testing.T) {
+	clickRepo := &fakeClickRepo{deltas: make(map[int64]int64)}
+
+	p := NewPipeline(clickRepo, nil, Config{
+		QueueSize:     10,
+		Workers:       1,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+	}, logger.New("error", "text"))
+	assert.NoError(t, p.Start(context.Background()))
+
+	assert.NoError(t, p.Enqueue(context.Background(), &entity.LinkClick{LinkID: 1}))
+	assert.NoError(t, p.Enqueue(context.Background(), &entity.LinkClick{LinkID: 1, IsBot: true}))
+	assert.NoError(t, p.Enqueue(context.Background(), &entity.LinkClick{LinkID: 2, IsBot: true}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, p.Shutdown(ctx))
+
+	// Все клики записаны, но счетчик увеличен только для клика человека
+	assert.Len(t, clickRepo.batches, 1)
+	assert.Len(t, clickRepo.batches[0], 3)
+	assert.Equal(t, map[int64]int64{1: 1}, clickRepo.deltas)
+}
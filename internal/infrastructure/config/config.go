@@ -16,8 +16,15 @@ type Config struct {
 	App       AppConfig
 	Database  DatabaseConfig
 	Redis     RedisConfig
+	Cache     CacheConfig
+	Clicks    ClicksConfig
+	GeoIP     GeoIPConfig
+	Bots      BotsConfig
+	Rollup    RollupConfig
+	Sweeper   SweeperConfig
 	JWT       JWTConfig
 	URL       URLConfig
+	Links     LinksConfig
 	CORS      CORSConfig
 	RateLimit RateLimitConfig
 	Log       LogConfig
@@ -49,10 +56,60 @@ type RedisConfig struct {
 	DB       int
 }
 
+// CacheConfig holds link cache configuration
+type CacheConfig struct {
+	Enabled            bool
+	LinkTTLMinutes     int
+	NegativeTTLSeconds int // TTL for cached "not found" lookups
+}
+
+// ClicksConfig holds click ingestion pipeline configuration
+type ClicksConfig struct {
+	Async                  bool
+	QueueSize              int
+	Workers                int
+	BatchSize              int
+	FlushIntervalMs        int
+	RedisStream            string // empty disables the Redis stream buffer
+	StreamMaxLen           int64
+	StreamClaimIdleSeconds int    // idle time after which messages left unacknowledged by another consumer are taken over
+	IPHashSalt             string // salt for IP hashes in the click log API; empty falls back to the JWT secret
+}
+
+// GeoIPConfig holds click geolocation configuration
+type GeoIPConfig struct {
+	DBPath string // path to a MaxMind-format (.mmdb) city database; empty disables geolocation
+}
+
+// BotsConfig holds bot detection rules in addition to the built-in User-Agent signatures
+type BotsConfig struct {
+	ExtraSignatures  []string // User-Agent substrings treated as bots
+	IgnoreSignatures []string // User-Agent substrings never treated as bots, even if a signature matches
+}
+
+// RollupConfig holds configuration of the background click aggregator
+type RollupConfig struct {
+	Enabled         bool
+	IntervalSeconds int
+	GraceMinutes    int // how long to wait for late clicks before an hour is aggregated
+	MaxHoursPerRun  int
+}
+
+// SweeperConfig holds configuration of the background cleanup of expired and deleted links
+type SweeperConfig struct {
+	Enabled         bool
+	IntervalSeconds int
+	GraceHours      int // how long an expired link keeps its short code so the owner can extend it
+	BatchSize       int
+	Mode            string // "archive" copies links to links_archive before deleting, "delete" drops them
+	TrashDays       int    // how long deleted links stay in the trash before they are purged
+}
+
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret      string
-	ExpireHours int
+	Secret              string
+	AccessExpireMinutes int
+	RefreshExpireHours  int
 }
 
 // URLConfig holds URL configuration
@@ -62,6 +119,13 @@ type URLConfig struct {
 	APIHost        string // Host for API documentation
 }
 
+// LinksConfig holds redirect responses for links that cannot be opened
+type LinksConfig struct {
+	NotStartedStatus  int // HTTP status returned before a link's starts_at
+	NotStartedMessage string
+	FallbackURL       string // where to send visitors of unknown codes and links without a link or account fallback
+}
+
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
 	AllowOrigins []string
@@ -71,8 +135,11 @@ type CORSConfig struct {
 
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
-	Requests      int
-	WindowMinutes int
+	Requests              int
+	WindowMinutes         int
+	PasswordAttempts      int // password attempts allowed per protected link and IP within the window
+	PasswordLinkAttempts  int // password attempts allowed per protected link from all IPs within the window
+	PasswordWindowMinutes int
 }
 
 // LogConfig holds logging configuration
@@ -114,23 +181,69 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+		Cache: CacheConfig{
+			Enabled:            getEnvAsBool("CACHE_ENABLED", true),
+			LinkTTLMinutes:     getEnvAsInt("CACHE_LINK_TTL_MINUTES", 10),
+			NegativeTTLSeconds: getEnvAsInt("CACHE_NEGATIVE_TTL_SECONDS", 30),
+		},
+		Clicks: ClicksConfig{
+			Async:                  getEnvAsBool("CLICKS_ASYNC", true),
+			QueueSize:              getEnvAsInt("CLICKS_QUEUE_SIZE", 10000),
+			Workers:                getEnvAsInt("CLICKS_WORKERS", 2),
+			BatchSize:              getEnvAsInt("CLICKS_BATCH_SIZE", 500),
+			FlushIntervalMs:        getEnvAsInt("CLICKS_FLUSH_INTERVAL_MS", 1000),
+			RedisStream:            getEnv("CLICKS_REDIS_STREAM", ""),
+			StreamMaxLen:           int64(getEnvAsInt("CLICKS_STREAM_MAX_LEN", 1000000)),
+			StreamClaimIdleSeconds: getEnvAsInt("CLICKS_STREAM_CLAIM_IDLE_SECONDS", 300),
+			IPHashSalt:             getEnv("CLICKS_IP_HASH_SALT", ""),
+		},
+		GeoIP: GeoIPConfig{
+			DBPath: getEnv("GEOIP_DB_PATH", ""),
+		},
+		Bots: BotsConfig{
+			ExtraSignatures:  getEnvAsStringSlice("BOTS_EXTRA_SIGNATURES", nil),
+			IgnoreSignatures: getEnvAsStringSlice("BOTS_IGNORE_SIGNATURES", nil),
+		},
+		Rollup: RollupConfig{
+			Enabled:         getEnvAsBool("ROLLUP_ENABLED", true),
+			IntervalSeconds: getEnvAsInt("ROLLUP_INTERVAL_SECONDS", 60),
+			GraceMinutes:    getEnvAsInt("ROLLUP_GRACE_MINUTES", 10),
+			MaxHoursPerRun:  getEnvAsInt("ROLLUP_MAX_HOURS_PER_RUN", 168),
+		},
+		Sweeper: SweeperConfig{
+			Enabled:         getEnvAsBool("SWEEPER_ENABLED", false),
+			IntervalSeconds: getEnvAsInt("SWEEPER_INTERVAL_SECONDS", 300),
+			GraceHours:      getEnvAsInt("SWEEPER_GRACE_HOURS", 168),
+			BatchSize:       getEnvAsInt("SWEEPER_BATCH_SIZE", 500),
+			Mode:            getEnv("SWEEPER_MODE", "archive"),
+			TrashDays:       getEnvAsInt("SWEEPER_TRASH_DAYS", 30),
+		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "your-secret-key-here"),
-			ExpireHours: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+			Secret:              getEnv("JWT_SECRET", "your-secret-key-here"),
+			AccessExpireMinutes: getEnvAsInt("JWT_ACCESS_EXPIRE_MINUTES", 15),
+			RefreshExpireHours:  getEnvAsInt("JWT_REFRESH_EXPIRE_HOURS", 720),
 		},
 		URL: URLConfig{
 			BaseURL:        getEnv("BASE_URL", "http://localhost:8080"),
 			ShortURLLength: getEnvAsInt("SHORT_URL_LENGTH", 6),
 			APIHost:        getEnv("API_HOST", "localhost:8080"),
 		},
+		Links: LinksConfig{
+			NotStartedStatus:  getEnvAsInt("LINK_NOT_STARTED_STATUS", 403),
+			NotStartedMessage: getEnv("LINK_NOT_STARTED_MESSAGE", "Link is not available yet"),
+			FallbackURL:       getEnv("LINK_FALLBACK_URL", ""),
+		},
 		CORS: CORSConfig{
 			AllowOrigins: getEnvAsStringSlice("CORS_ALLOW_ORIGINS", []string{"*"}),
 			AllowMethods: getEnvAsStringSlice("CORS_ALLOW_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}),
 			AllowHeaders: getEnvAsStringSlice("CORS_ALLOW_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
 		},
 		RateLimit: RateLimitConfig{
-			Requests:      getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
-			WindowMinutes: getEnvAsInt("RATE_LIMIT_WINDOW_MINUTES", 1),
+			Requests:              getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
+			WindowMinutes:         getEnvAsInt("RATE_LIMIT_WINDOW_MINUTES", 1),
+			PasswordAttempts:      getEnvAsInt("RATE_LIMIT_PASSWORD_ATTEMPTS", 5),
+			PasswordLinkAttempts:  getEnvAsInt("RATE_LIMIT_PASSWORD_LINK_ATTEMPTS", 50),
+			PasswordWindowMinutes: getEnvAsInt("RATE_LIMIT_PASSWORD_WINDOW_MINUTES", 15),
 		},
 		Log: LogConfig{
 			Level:    getEnv("LOG_LEVEL", "debug"),
@@ -152,6 +265,7 @@ func Load() (*Config, error) {
 	configCopy.Database.Password = "[MASKED]"
 	configCopy.Redis.Password = "[MASKED]"
 	configCopy.JWT.Secret = "[MASKED]"
+	configCopy.Clicks.IPHashSalt = "[MASKED]"
 
 	// Конвертируем конфиг в JSON для логирования
 	configJSON, err := json.MarshalIndent(configCopy, "", "  ")
@@ -0,0 +1,65 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+)
+
+// Resolver определяет страну и город по локальной базе в формате MaxMind (GeoLite2-City, GeoIP2-City и совместимые)
+type Resolver struct {
+	reader *maxminddb.Reader
+}
+
+// cityRecord - поля записи базы, которые нужны резолверу
+type cityRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// NewResolver открывает файл базы и возвращает резолвер
+func NewResolver(path string) (*Resolver, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+	return &Resolver{reader: reader}, nil
+}
+
+// Lookup возвращает местоположение адреса или nil, если адреса нет в базе
+func (r *Resolver) Lookup(ip string) (*entity.GeoLocation, error) {
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return nil, nil
+	}
+
+	var record cityRecord
+	if err := r.reader.Lookup(addr, &record); err != nil {
+		return nil, fmt.Errorf("failed to lookup ip: %w", err)
+	}
+
+	location := &entity.GeoLocation{
+		Country: record.Country.ISOCode,
+		City:    record.City.Names["en"],
+	}
+	// В базах уровня страны у части сетей есть только registered_country
+	if location.Country == "" {
+		location.Country = record.RegisteredCountry.ISOCode
+	}
+
+	if location.Country == "" && location.City == "" {
+		return nil, nil
+	}
+	return location, nil
+}
@@ -0,0 +1,143 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Минимальный кодировщик формата MMDB для сборки тестовой базы
+
+const (
+	typeString = 2
+	typeUint16 = 5
+	typeUint32 = 6
+	typeMap    = 7
+)
+
+func encodeString(s string) []byte {
+	return append([]byte{byte(typeString<<5 | len(s))}, s...)
+}
+
+func encodeUint16(v uint16) []byte {
+	return []byte{byte(typeUint16<<5 | 2), byte(v >> 8), byte(v)}
+}
+
+func encodeUint32(v uint32) []byte {
+	b := []byte{byte(typeUint32<<5 | 4), 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(b[1:], v)
+	return b
+}
+
+func encodeMap(pairs ...[]byte) []byte {
+	out := []byte{byte(typeMap<<5 | len(pairs)/2)}
+	for _, p := range pairs {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// writeIPv4Database записывает базу с record size 24, в которой есть только сеть prefix/24, и возвращает путь к ней
+func writeIPv4Database(t *testing.T, prefix net.IP, record []byte) string {
+	t.Helper()
+
+	const depth = 24
+	const separatorSize = 16
+	nodeCount := uint32(depth)
+	key := prefix.To4()
+
+	var tree bytes.Buffer
+	for i := 0; i < depth; i++ {
+		bit := (key[i/8] >> (7 - uint(i%8))) & 1
+
+		next := uint32(i + 1)
+		if i == depth-1 {
+			next = nodeCount + separatorSize // данные по смещению 0
+		}
+
+		left, right := nodeCount, nodeCount
+		if bit == 0 {
+			left = next
+		} else {
+			right = next
+		}
+		tree.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)})
+	}
+
+	var file bytes.Buffer
+	file.Write(tree.Bytes())
+	file.Write(make([]byte, separatorSize))
+	file.Write(record)
+	file.WriteString("\xAB\xCD\xEFMaxMind.com")
+	file.Write(encodeMap(
+		encodeString("binary_format_major_version"), encodeUint16(2),
+		encodeString("binary_format_minor_version"), encodeUint16(0),
+		encodeString("node_count"), encodeUint32(nodeCount),
+		encodeString("record_size"), encodeUint16(24),
+		encodeString("ip_version"), encodeUint16(4),
+		encodeString("database_type"), encodeString("Test-City"),
+	))
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	if err := os.WriteFile(path, file.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolver_Lookup(t *testing.T) {
+	record := encodeMap(
+		encodeString("country"), encodeMap(
+			encodeString("iso_code"), encodeString("DE"),
+		),
+		encodeString("city"), encodeMap(
+			encodeString("names"), encodeMap(
+				encodeString("en"), encodeString("Berlin"),
+			),
+		),
+	)
+	resolver, err := NewResolver(writeIPv4Database(t, net.ParseIP("81.2.69.0"), record))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("Success - Address inside the network", func(t *testing.T) {
+		location, err := resolver.Lookup("81.2.69.160")
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, location) {
+			assert.Equal(t, "DE", location.Country)
+			assert.Equal(t, "Berlin", location.City)
+		}
+	})
+
+	t.Run("Success - Address outside the network", func(t *testing.T) {
+		location, err := resolver.Lookup("8.8.8.8")
+
+		assert.NoError(t, err)
+		assert.Nil(t, location)
+	})
+
+	t.Run("Success - Invalid address", func(t *testing.T) {
+		location, err := resolver.Lookup("not an ip")
+
+		assert.NoError(t, err)
+		assert.Nil(t, location)
+	})
+}
+
+func TestNewResolver_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.mmdb")
+	if err := os.WriteFile(path, []byte("not a database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewResolver(path)
+
+	assert.Error(t, err)
+}
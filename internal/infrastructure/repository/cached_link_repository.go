@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+// notFoundMarker хранится в кэше для коротких кодов, которых нет в базе
+const notFoundMarker = "-"
+
+// cacheSetScript записывает ссылку в кэш, только если с момента чтения из базы код не сбрасывался:
+// иначе чтение, начатое до изменения ссылки, вернуло бы в кэш старую версию после invalidate
+var cacheSetScript = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '') ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+type cachedLinkRepository struct {
+	next        repository.LinkRepository
+	redis       *redis.Client
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+// NewCachedLinkRepository оборачивает репозиторий ссылок read-through кэшем в Redis.
+// Кэшируются только поиски по короткому коду (горячий путь редиректа), включая
+// отсутствующие коды. Ссылки с паролем не кэшируются, чтобы хеш пароля не попадал в Redis:
+// они всегда читаются из базы. Ошибки Redis не прерывают запрос: чтение уходит в базу.
+func NewCachedLinkRepository(next repository.LinkRepository, redisClient *redis.Client, ttl, negativeTTL time.Duration) repository.LinkRepository {
+	return &cachedLinkRepository{
+		next:        next,
+		redis:       redisClient,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+}
+
+// shortCodeCacheKey строит ключ кэша. Версию в ключе нужно поднимать при изменении полей entity.Link
+// или состава кэшируемых данных: gob молча оставляет отсутствующие в старых записях поля нулевыми
+// (например, DisabledByAdmin). Хеш-тег держит ключ в одном слоте Redis Cluster с shortCodeGenerationKey
+func shortCodeCacheKey(shortCode string) string {
+	return fmt.Sprintf("link:v3:{%s}", shortCode)
+}
+
+// shortCodeGenerationKey хранит счетчик сбросов кэша кода, см. cacheSetScript
+func shortCodeGenerationKey(shortCode string) string {
+	return fmt.Sprintf("link:v3:{%s}:gen", shortCode)
+}
+
+func (r *cachedLinkRepository) Create(ctx context.Context, link *entity.Link) error {
+	if err := r.next.Create(ctx, link); err != nil {
+		return err
+	}
+
+	// Код мог быть закэширован как отсутствующий
+	r.invalidate(ctx, link.ShortCode)
+	return nil
+}
+
+func (r *cachedLinkRepository) CreateBatch(ctx context.Context, links []*entity.Link) error {
+	if err := r.next.CreateBatch(ctx, links); err != nil {
+		return err
+	}
+
+	codes := make([]string, len(links))
+	for i, link := range links {
+		codes[i] = link.ShortCode
+	}
+	r.invalidate(ctx, codes...)
+	return nil
+}
+
+func (r *cachedLinkRepository) GetByShortCode(ctx context.Context, shortCode string) (*entity.Link, error) {
+	key := shortCodeCacheKey(shortCode)
+
+	data, err := r.redis.Get(ctx, key).Bytes()
+	if err == nil {
+		if string(data) == notFoundMarker {
+			return nil, nil
+		}
+		if link, err := decodeLink(data); err == nil {
+			return link, nil
+		}
+	}
+
+	// Поколение читается до базы: если ссылку изменят, пока идет чтение, запись в кэш не состоится
+	generation, err := r.redis.Get(ctx, shortCodeGenerationKey(shortCode)).Result()
+	cacheable := err == nil || err == redis.Nil
+
+	link, err := r.next.GetByShortCode(ctx, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cacheable {
+		return link, nil
+	}
+
+	if link == nil {
+		r.set(ctx, shortCode, generation, []byte(notFoundMarker), r.negativeTTL)
+		return nil, nil
+	}
+
+	if link.IsPasswordProtected() {
+		return link, nil
+	}
+
+	if encoded, err := encodeLink(link); err == nil {
+		r.set(ctx, shortCode, generation, encoded, r.ttl)
+	}
+
+	return link, nil
+}
+
+func (r *cachedLinkRepository) GetByID(ctx context.Context, id int64) (*entity.Link, error) {
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *cachedLinkRepository) GetByIDs(ctx context.Context, ids []int64) ([]*entity.Link, error) {
+	return r.next.GetByIDs(ctx, ids)
+}
+
+func (r *cachedLinkRepository) GetByUserID(ctx context.Context, userID int64, offset, limit int) ([]*entity.Link, error) {
+	return r.next.GetByUserID(ctx, userID, offset, limit)
+}
+
+func (r *cachedLinkRepository) Update(ctx context.Context, link *entity.Link) error {
+	// Берем текущую версию из базы: короткий код мог измениться
+	current, err := r.next.GetByID(ctx, link.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.next.Update(ctx, link); err != nil {
+		return err
+	}
+
+	if current != nil {
+		r.invalidate(ctx, current.ShortCode)
+	}
+	r.invalidate(ctx, link.ShortCode)
+	return nil
+}
+
+func (r *cachedLinkRepository) SetDisabledByAdmin(ctx context.Context, id int64, disabled bool) error {
+	current, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.next.SetDisabledByAdmin(ctx, id, disabled); err != nil {
+		return err
+	}
+
+	if current != nil {
+		r.invalidate(ctx, current.ShortCode)
+	}
+	return nil
+}
+
+func (r *cachedLinkRepository) Delete(ctx context.Context, id int64) error {
+	current, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if current != nil {
+		r.invalidate(ctx, current.ShortCode)
+	}
+	return nil
+}
+
+func (r *cachedLinkRepository) GetDeletedByID(ctx context.Context, id int64) (*entity.Link, error) {
+	return r.next.GetDeletedByID(ctx, id)
+}
+
+func (r *cachedLinkRepository) Restore(ctx context.Context, id int64) error {
+	deleted, err := r.next.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.next.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	// Пока ссылка была в корзине, код мог попасть в кэш как отсутствующий
+	if deleted != nil {
+		r.invalidate(ctx, deleted.ShortCode)
+	}
+	return nil
+}
+
+func (r *cachedLinkRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	codes, err := r.next.PurgeDeleted(ctx, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidate(ctx, codes...)
+	return codes, nil
+}
+
+// IncrementClicks не сбрасывает кэш: счетчик кликов в кэшированной копии
+// может отставать, для редиректа он не используется
+func (r *cachedLinkRepository) IncrementClicks(ctx context.Context, linkID int64) error {
+	return r.next.IncrementClicks(ctx, linkID)
+}
+
+func (r *cachedLinkRepository) GetFallbackURL(ctx context.Context, shortCode string) (string, error) {
+	return r.next.GetFallbackURL(ctx, shortCode)
+}
+
+// ConsumeUse не сбрасывает кэш: лимит проверяется в базе, кэшированная копия
+// только позволяет отказать раньше, когда ссылка уже исчерпана
+func (r *cachedLinkRepository) ConsumeUse(ctx context.Context, linkID int64) (int64, bool, error) {
+	return r.next.ConsumeUse(ctx, linkID)
+}
+
+func (r *cachedLinkRepository) GetExpiredLinks(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error) {
+	return r.next.GetExpiredLinks(ctx, before, limit)
+}
+
+func (r *cachedLinkRepository) PurgeExpired(ctx context.Context, ids []int64, before time.Time, archive bool) ([]string, error) {
+	codes, err := r.next.PurgeExpired(ctx, ids, before, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		r.invalidate(ctx, code)
+	}
+	return codes, nil
+}
+
+func (r *cachedLinkRepository) CountByUserID(ctx context.Context, userID int64) (int64, error) {
+	return r.next.CountByUserID(ctx, userID)
+}
+
+func (r *cachedLinkRepository) ExistsByShortCode(ctx context.Context, shortCode string) (bool, error) {
+	return r.next.ExistsByShortCode(ctx, shortCode)
+}
+
+func (r *cachedLinkRepository) FindExistingShortCodes(ctx context.Context, shortCodes []string) ([]string, error) {
+	return r.next.FindExistingShortCodes(ctx, shortCodes)
+}
+
+func (r *cachedLinkRepository) List(ctx context.Context, filter entity.LinkFilter) ([]*entity.Link, error) {
+	return r.next.List(ctx, filter)
+}
+
+func (r *cachedLinkRepository) Count(ctx context.Context, filter entity.LinkFilter) (int64, error) {
+	return r.next.Count(ctx, filter)
+}
+
+// set кэширует значение кода, если его поколение не изменилось с момента чтения generation
+func (r *cachedLinkRepository) set(ctx context.Context, shortCode, generation string, value []byte, ttl time.Duration) {
+	keys := []string{shortCodeCacheKey(shortCode), shortCodeGenerationKey(shortCode)}
+	cacheSetScript.Run(ctx, r.redis, keys, generation, value, ttl.Milliseconds())
+}
+
+// invalidate удаляет закэшированные записи по коротким кодам и увеличивает их поколение,
+// чтобы уже начатые чтения не записали в кэш прежнюю версию
+func (r *cachedLinkRepository) invalidate(ctx context.Context, shortCodes ...string) {
+	// Поколение должно пережить любое начатое чтение
+	generationTTL := max(r.ttl, r.negativeTTL)
+
+	pipe := r.redis.Pipeline()
+	for _, code := range shortCodes {
+		if code == "" {
+			continue
+		}
+		pipe.Incr(ctx, shortCodeGenerationKey(code))
+		pipe.Expire(ctx, shortCodeGenerationKey(code), generationTTL)
+		pipe.Del(ctx, shortCodeCacheKey(code))
+	}
+	if pipe.Len() > 0 {
+		pipe.Exec(ctx)
+	}
+}
+
+// encodeLink сериализует ссылку в gob, чтобы в кэш попадали все поля сущности,
+// включая скрытые из JSON
+func encodeLink(link *entity.Link) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(link); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeLink(data []byte) (*entity.Link, error) {
+	var link entity.Link
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLinkStore хранит ссылки в памяти и считает обращения к базе
+type fakeLinkStore struct {
+	repository.LinkRepository
+
+	links   map[int64]*entity.Link
+	lookups int
+	// afterLookup вызывается после чтения ссылки, до возврата результата
+	afterLookup func()
+}
+
+func (s *fakeLinkStore) GetByShortCode(ctx context.Context, shortCode string) (*entity.Link, error) {
+	s.lookups++
+	var found *entity.Link
+	for _, link := range s.links {
+		if link.ShortCode == shortCode {
+			copied := *link
+			found = &copied
+		}
+	}
+	if s.afterLookup != nil {
+		s.afterLookup()
+	}
+	return found, nil
+}
+
+func (s *fakeLinkStore) GetByID(ctx context.Context, id int64) (*entity.Link, error) {
+	link, ok := s.links[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (s *fakeLinkStore) Create(ctx context.Context, link *entity.Link) error {
+	copied := *link
+	s.links[link.ID] = &copied
+	return nil
+}
+
+func (s *fakeLinkStore) Update(ctx context.Context, link *entity.Link) error {
+	copied := *link
+	s.links[link.ID] = &copied
+	return nil
+}
+
+func (s *fakeLinkStore) Delete(ctx context.Context, id int64) error {
+	delete(s.links, id)
+	return nil
+}
+
+func newCachedLinkRepositoryForTest(t *testing.T, links ...*entity.Link) (repository.LinkRepository, *fakeLinkStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	store := &fakeLinkStore{links: make(map[int64]*entity.Link)}
+	for _, link := range links {
+		store.links[link.ID] = link
+	}
+
+	return NewCachedLinkRepository(store, client, time.Hour, time.Minute), store, server
+}
+
+func TestCachedLinkRepository_GetByShortCode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - Miss reads the database and fills the cache", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://example.com"})
+
+		link, err := repo.GetByShortCode(ctx, "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com", link.OriginalURL)
+		assert.Equal(t, 1, store.lookups)
+		assert.True(t, server.Exists(shortCodeCacheKey("abc")))
+		assert.Equal(t, time.Hour, server.TTL(shortCodeCacheKey("abc")))
+	})
+
+	t.Run("Success - Hit is served from the cache", func(t *testing.T) {
+		repo, store, _ := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://example.com", DisabledByAdmin: true})
+
+		_, err := repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		link, err := repo.GetByShortCode(ctx, "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), link.ID)
+		assert.Equal(t, "https://example.com", link.OriginalURL)
+		assert.True(t, link.DisabledByAdmin)
+		assert.Equal(t, 1, store.lookups)
+	})
+
+	t.Run("Success - Unknown code is cached with the negative TTL", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t)
+
+		link, err := repo.GetByShortCode(ctx, "missing")
+		assert.NoError(t, err)
+		assert.Nil(t, link)
+
+		link, err = repo.GetByShortCode(ctx, "missing")
+		assert.NoError(t, err)
+		assert.Nil(t, link)
+
+		assert.Equal(t, 1, store.lookups)
+		value, _ := server.Get(shortCodeCacheKey("missing"))
+		assert.Equal(t, notFoundMarker, value)
+		assert.Equal(t, time.Minute, server.TTL(shortCodeCacheKey("missing")))
+	})
+
+	t.Run("Success - Negative entry expires", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t)
+
+		_, err := repo.GetByShortCode(ctx, "later")
+		assert.NoError(t, err)
+
+		store.links[1] = &entity.Link{ID: 1, ShortCode: "later", OriginalURL: "https://example.com"}
+		server.FastForward(time.Minute)
+
+		link, err := repo.GetByShortCode(ctx, "later")
+		assert.NoError(t, err)
+		if assert.NotNil(t, link) {
+			assert.Equal(t, "https://example.com", link.OriginalURL)
+		}
+		assert.Equal(t, 2, store.lookups)
+	})
+
+	t.Run("Success - Password protected link is not cached", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://example.com", PasswordHash: "hash"})
+
+		link, err := repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, "hash", link.PasswordHash)
+		_, err = repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+
+		assert.False(t, server.Exists(shortCodeCacheKey("abc")))
+		assert.Equal(t, 2, store.lookups)
+	})
+
+	t.Run("Success - Broken entry falls back to the database", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://example.com"})
+		assert.NoError(t, server.Set(shortCodeCacheKey("abc"), "not gob"))
+
+		link, err := repo.GetByShortCode(ctx, "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com", link.OriginalURL)
+		assert.Equal(t, 1, store.lookups)
+	})
+
+	t.Run("Success - Redis outage falls back to the database", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://example.com"})
+		server.Close()
+
+		link, err := repo.GetByShortCode(ctx, "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com", link.OriginalURL)
+		assert.Equal(t, 1, store.lookups)
+	})
+}
+
+func TestCachedLinkRepository_Invalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - Create clears a negative entry", func(t *testing.T) {
+		repo, _, server := newCachedLinkRepositoryForTest(t)
+
+		_, err := repo.GetByShortCode(ctx, "new")
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Create(ctx, &entity.Link{ID: 1, ShortCode: "new", OriginalURL: "https://example.com"}))
+
+		assert.False(t, server.Exists(shortCodeCacheKey("new")))
+		link, err := repo.GetByShortCode(ctx, "new")
+		assert.NoError(t, err)
+		assert.NotNil(t, link)
+	})
+
+	t.Run("Success - Update drops the cached link", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://old.example.com"})
+
+		_, err := repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Update(ctx, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://new.example.com"}))
+
+		assert.False(t, server.Exists(shortCodeCacheKey("abc")))
+		link, err := repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://new.example.com", link.OriginalURL)
+		assert.Equal(t, 2, store.lookups)
+	})
+
+	t.Run("Success - Update with a new short code drops both codes", func(t *testing.T) {
+		repo, _, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "old", OriginalURL: "https://example.com"})
+
+		_, err := repo.GetByShortCode(ctx, "old")
+		assert.NoError(t, err)
+		_, err = repo.GetByShortCode(ctx, "new")
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Update(ctx, &entity.Link{ID: 1, ShortCode: "new", OriginalURL: "https://example.com"}))
+
+		assert.False(t, server.Exists(shortCodeCacheKey("old")))
+		assert.False(t, server.Exists(shortCodeCacheKey("new")))
+
+		link, err := repo.GetByShortCode(ctx, "old")
+		assert.NoError(t, err)
+		assert.Nil(t, link)
+		link, err = repo.GetByShortCode(ctx, "new")
+		assert.NoError(t, err)
+		assert.NotNil(t, link)
+	})
+
+	t.Run("Success - Read started before an update does not cache the old version", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://old.example.com"})
+
+		store.afterLookup = func() {
+			store.afterLookup = nil
+			assert.NoError(t, repo.Update(ctx, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://new.example.com"}))
+		}
+		link, err := repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://old.example.com", link.OriginalURL)
+		assert.False(t, server.Exists(shortCodeCacheKey("abc")))
+
+		link, err = repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://new.example.com", link.OriginalURL)
+		assert.True(t, server.Exists(shortCodeCacheKey("abc")))
+	})
+
+	t.Run("Success - Delete drops the cached link", func(t *testing.T) {
+		repo, store, server := newCachedLinkRepositoryForTest(t, &entity.Link{ID: 1, ShortCode: "abc", OriginalURL: "https://example.com"})
+
+		_, err := repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Delete(ctx, 1))
+
+		assert.False(t, server.Exists(shortCodeCacheKey("abc")))
+		link, err := repo.GetByShortCode(ctx, "abc")
+		assert.NoError(t, err)
+		assert.Nil(t, link)
+		assert.Equal(t, 2, store.lookups)
+	})
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+// refererHostExpr выделяет хост из referer в нижнем регистре; пустая строка - прямой переход
+const refererHostExpr = `LOWER(COALESCE(SUBSTRING(referer FROM '^[a-zA-Z][a-zA-Z0-9+.-]*://([^/?#:]+)'), ''))`
+
+type clickRollupRepository struct {
+	db *sql.DB
+}
+
+// NewClickRollupRepository создает новый репозиторий агрегатов кликов
+func NewClickRollupRepository(db *sql.DB) repository.ClickRollupRepository {
+	return &clickRollupRepository{db: db}
+}
+
+func (r *clickRollupRepository) RollUp(ctx context.Context, until time.Time, maxHours int) (time.Time, error) {
+	until = until.UTC().Truncate(time.Hour)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	// Первый запуск начинает с часа самого старого клика
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO click_rollup_state (id, rolled_up_to)
+		SELECT 1, COALESCE(date_trunc('hour', MIN(clicked_at) AT TIME ZONE 'UTC') AT TIME ZONE 'UTC', $1)
+		FROM link_clicks
+		ON CONFLICT (id) DO NOTHING
+	`, until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to init rollup state: %w", err)
+	}
+
+	// Блокировка строки состояния не дает двум экземплярам агрегировать одновременно
+	var start time.Time
+	err = tx.QueryRowContext(ctx, `SELECT rolled_up_to FROM click_rollup_state WHERE id = 1 FOR UPDATE`).Scan(&start)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to lock rollup state: %w", err)
+	}
+	start = start.UTC()
+
+	end := start
+	if start.Before(until) {
+		end = until
+		if maxHours > 0 && end.Sub(start) > time.Duration(maxHours)*time.Hour {
+			end = start.Add(time.Duration(maxHours) * time.Hour)
+		}
+	}
+
+	// Сутки пересчитываются из часовых агрегатов, когда закрыт их последний час
+	days := make(map[time.Time]bool)
+
+	if end.After(start) {
+		if err := rebuildHourlyRollups(ctx, tx, start, end); err != nil {
+			return time.Time{}, err
+		}
+		for day := start.Truncate(24 * time.Hour); day.Before(end); day = day.Add(24 * time.Hour) {
+			days[day] = true
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE click_rollup_state SET rolled_up_to = $1 WHERE id = 1`, end); err != nil {
+			return time.Time{}, fmt.Errorf("failed to update rollup state: %w", err)
+		}
+	}
+
+	// Грязные часы читаются после агрегации периода: отметки кликов, пришедших во время нее,
+	// попадут в пересчет сейчас или на следующем запуске
+	dirty, err := takeDirtyHours(ctx, tx, end, maxHours)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, hour := range dirty {
+		if err := rebuildHourlyRollups(ctx, tx, hour, hour.Add(time.Hour)); err != nil {
+			return time.Time{}, err
+		}
+		days[hour.Truncate(24*time.Hour)] = true
+	}
+
+	for day := range days {
+		if day.Add(24 * time.Hour).After(end) {
+			continue
+		}
+		if err := rebuildDailyRollups(ctx, tx, day, day.Add(24*time.Hour)); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return end, tx.Commit()
+}
+
+// takeDirtyHours забирает до maxHours часов ниже watermark, в которые клики пришли уже после агрегации.
+// Отметки часов от watermark и позже удаляются без пересчета: эти часы еще будут агрегированы целиком
+func takeDirtyHours(ctx context.Context, tx *sql.Tx, watermark time.Time, maxHours int) ([]time.Time, error) {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM click_rollup_dirty_hours WHERE bucket >= $1`, watermark); err != nil {
+		return nil, fmt.Errorf("failed to clear pending dirty rollup hours: %w", err)
+	}
+
+	limit := maxHours
+	if limit <= 0 {
+		limit = 24 * 7
+	}
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM click_rollup_dirty_hours
+		WHERE bucket IN (SELECT bucket FROM click_rollup_dirty_hours ORDER BY bucket LIMIT $1)
+		RETURNING bucket
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take dirty rollup hours: %w", err)
+	}
+	defer rows.Close()
+
+	var hours []time.Time
+	for rows.Next() {
+		var hour time.Time
+		if err := rows.Scan(&hour); err != nil {
+			return nil, err
+		}
+		hours = append(hours, hour.UTC())
+	}
+	return hours, rows.Err()
+}
+
+// rebuildHourlyRollups пересобирает часовые агрегаты [from, to) из сырых кликов
+func rebuildHourlyRollups(ctx context.Context, tx *sql.Tx, from, to time.Time) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM link_click_rollups_hourly WHERE bucket >= $1 AND bucket < $2`, from, to); err != nil {
+		return fmt.Errorf("failed to clear hourly rollups: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO link_click_rollups_hourly (link_id, bucket, country, device_type, os, browser, referer_host, is_bot, clicks)
+		SELECT
+			link_id,
+			date_trunc('hour', clicked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+			COALESCE(country, ''),
+			COALESCE(device_type, ''),
+			COALESCE(os, ''),
+			COALESCE(browser, ''),
+			%s,
+			is_bot,
+			COUNT(*)
+		FROM link_clicks
+		WHERE clicked_at >= $1 AND clicked_at < $2
+		GROUP BY 1, 2, 3, 4, 5, 6, 7, 8
+	`, refererHostExpr)
+	if _, err := tx.ExecContext(ctx, query, from, to); err != nil {
+		return fmt.Errorf("failed to build hourly rollups: %w", err)
+	}
+	return nil
+}
+
+// rebuildDailyRollups пересобирает суточные агрегаты [from, to) из часовых
+func rebuildDailyRollups(ctx context.Context, tx *sql.Tx, from, to time.Time) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM link_click_rollups_daily WHERE bucket >= $1 AND bucket < $2`, from, to); err != nil {
+		return fmt.Errorf("failed to clear daily rollups: %w", err)
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO link_click_rollups_daily (link_id, bucket, country, device_type, os, browser, referer_host, is_bot, clicks)
+		SELECT
+			link_id,
+			date_trunc('day', bucket AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+			country, device_type, os, browser, referer_host, is_bot,
+			SUM(clicks)
+		FROM link_click_rollups_hourly
+		WHERE bucket >= $1 AND bucket < $2
+		GROUP BY 1, 2, 3, 4, 5, 6, 7, 8
+	`, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to build daily rollups: %w", err)
+	}
+	return nil
+}
+
+// rollupWatermark возвращает момент, до которого клики агрегированы; нулевое время - агрегатов нет
+func rollupWatermark(ctx context.Context, db *sql.DB) (time.Time, error) {
+	var watermark time.Time
+	err := db.QueryRowContext(ctx, `SELECT rolled_up_to FROM click_rollup_state WHERE id = 1`).Scan(&watermark)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return watermark.UTC(), nil
+}
+
+// statsRanges делит период статистики между суточными и часовыми агрегатами и сырыми кликами:
+// суточные - [dayFrom, dayTo), часовые - [hourFrom, dayFrom) и [dayTo, hourTo),
+// сырые клики - [from, hourFrom) и [hourTo, to]
+type statsRanges struct {
+	hourFrom, hourTo time.Time
+	dayFrom, dayTo   time.Time
+}
+
+// splitStatsRange строит statsRanges. Без агрегатов все границы равны from и период целиком читается из сырых кликов
+func splitStatsRange(from, to, watermark time.Time) statsRanges {
+	from, to, watermark = from.UTC(), to.UTC(), watermark.UTC()
+	none := statsRanges{hourFrom: from, hourTo: from, dayFrom: from, dayTo: from}
+
+	hourFrom := ceilTo(from, time.Hour)
+	hourTo := to
+	if watermark.Before(hourTo) {
+		hourTo = watermark
+	}
+	hourTo = hourTo.Truncate(time.Hour)
+	if !hourTo.After(hourFrom) {
+		return none
+	}
+
+	r := statsRanges{hourFrom: hourFrom, hourTo: hourTo, dayFrom: hourFrom, dayTo: hourFrom}
+	dayFrom := ceilTo(hourFrom, 24*time.Hour)
+	dayTo := hourTo.Truncate(24 * time.Hour)
+	if dayTo.After(dayFrom) {
+		r.dayFrom, r.dayTo = dayFrom, dayTo
+	}
+	return r
+}
+
+// ceilTo округляет t вверх до кратного d от начала эпохи (в UTC)
+func ceilTo(t time.Time, d time.Duration) time.Time {
+	truncated := t.Truncate(d)
+	if truncated.Before(t) {
+		return truncated.Add(d)
+	}
+	return truncated
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatsRange(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("Success - Days, hours and raw edges", func(t *testing.T) {
+		r := splitStatsRange(at(1, 10, 30), at(5, 14, 20), at(5, 13, 0))
+
+		assert.Equal(t, at(1, 11, 0), r.hourFrom)
+		assert.Equal(t, at(5, 13, 0), r.hourTo)
+		assert.Equal(t, at(2, 0, 0), r.dayFrom)
+		assert.Equal(t, at(5, 0, 0), r.dayTo)
+	})
+
+	t.Run("Success - Period shorter than a day uses only hours", func(t *testing.T) {
+		r := splitStatsRange(at(1, 10, 0), at(1, 18, 0), at(2, 0, 0))
+
+		assert.Equal(t, at(1, 10, 0), r.hourFrom)
+		assert.Equal(t, at(1, 18, 0), r.hourTo)
+		assert.Equal(t, r.dayFrom, r.dayTo)
+	})
+
+	t.Run("Success - Nothing rolled up reads raw clicks only", func(t *testing.T) {
+		r := splitStatsRange(at(1, 10, 0), at(5, 0, 0), time.Time{})
+
+		assert.Equal(t, at(1, 10, 0), r.hourFrom)
+		assert.Equal(t, r.hourFrom, r.hourTo)
+		assert.Equal(t, r.hourFrom, r.dayTo)
+	})
+}
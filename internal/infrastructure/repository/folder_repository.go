@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+type folderRepository struct {
+	db *sql.DB
+}
+
+// NewFolderRepository создает новый репозиторий папок
+func NewFolderRepository(db *sql.DB) repository.FolderRepository {
+	return &folderRepository{db: db}
+}
+
+// folderColumns - список колонок для выборки папок, порядок совпадает со scanFolder
+const folderColumns = `id, user_id, name, created_at, updated_at`
+
+// scanFolder читает папку из строки результата
+func scanFolder(row rowScanner) (*entity.Folder, error) {
+	var folder entity.Folder
+	err := row.Scan(&folder.ID, &folder.UserID, &folder.Name, &folder.CreatedAt, &folder.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *folderRepository) Create(ctx context.Context, folder *entity.Folder) error {
+	query := `
+		INSERT INTO folders (user_id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	now := time.Now()
+	folder.CreatedAt = now
+	folder.UpdatedAt = now
+
+	return r.db.QueryRowContext(ctx, query, folder.UserID, folder.Name, folder.CreatedAt, folder.UpdatedAt).Scan(&folder.ID)
+}
+
+func (r *folderRepository) GetByID(ctx context.Context, id int64) (*entity.Folder, error) {
+	query := `SELECT ` + folderColumns + ` FROM folders WHERE id = $1`
+
+	folder, err := scanFolder(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return folder, nil
+}
+
+func (r *folderRepository) GetByName(ctx context.Context, userID int64, name string) (*entity.Folder, error) {
+	query := `SELECT ` + folderColumns + ` FROM folders WHERE user_id = $1 AND name = $2`
+
+	folder, err := scanFolder(r.db.QueryRowContext(ctx, query, userID, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return folder, nil
+}
+
+func (r *folderRepository) GetByUserID(ctx context.Context, userID int64) ([]*entity.Folder, error) {
+	query := `SELECT ` + folderColumns + ` FROM folders WHERE user_id = $1 ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := make([]*entity.Folder, 0)
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+
+	return folders, rows.Err()
+}
+
+func (r *folderRepository) Update(ctx context.Context, folder *entity.Folder) error {
+	query := `UPDATE folders SET name = $1, updated_at = $2 WHERE id = $3`
+
+	folder.UpdatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, query, folder.Name, folder.UpdatedAt, folder.ID)
+	return err
+}
+
+func (r *folderRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM folders WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
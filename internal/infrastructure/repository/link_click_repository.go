@@ -3,12 +3,20 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
 )
 
+// maxUniqueClicksRange ограничивает период, за который уникальные посетители считаются по сырым кликам
+const maxUniqueClicksRange = 90 * 24 * time.Hour
+
 type linkClickRepository struct {
 	db *sql.DB
 }
@@ -20,8 +28,8 @@ func NewLinkClickRepository(db *sql.DB) repository.LinkClickRepository {
 
 func (r *linkClickRepository) Create(ctx context.Context, click *entity.LinkClick) error {
 	query := `
-		INSERT INTO link_clicks (link_id, ip_address, user_agent, referer, country, city, clicked_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO link_clicks (link_id, ip_address, user_agent, referer, country, city, device_type, os, browser, is_bot, clicked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10, $11)
 		RETURNING id
 	`
 
@@ -38,20 +46,158 @@ func (r *linkClickRepository) Create(ctx context.Context, click *entity.LinkClic
 		click.Referer,
 		click.Country,
 		click.City,
+		click.DeviceType,
+		click.OS,
+		click.Browser,
+		click.IsBot,
 		click.ClickedAt,
 	).Scan(&click.ID)
 }
 
-func (r *linkClickRepository) GetByLinkID(ctx context.Context, linkID int64, offset, limit int) ([]*entity.LinkClick, error) {
+func (r *linkClickRepository) CreateBatch(ctx context.Context, clicks []*entity.LinkClick, counts map[int64]int64) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	// Один INSERT на весь батч через unnest; клики удаленных ссылок отбрасываются
 	query := `
-		SELECT id, link_id, ip_address, user_agent, referer, country, city, clicked_at
-		FROM link_clicks
-		WHERE link_id = $1
-		ORDER BY clicked_at DESC
-		LIMIT $2 OFFSET $3
+		INSERT INTO link_clicks (link_id, ip_address, user_agent, referer, country, city, device_type, os, browser, is_bot, clicked_at)
+		SELECT v.link_id, v.ip_address, v.user_agent, NULLIF(v.referer, ''), NULLIF(v.country, ''), NULLIF(v.city, ''),
+			NULLIF(v.device_type, ''), NULLIF(v.os, ''), NULLIF(v.browser, ''), v.is_bot, v.clicked_at
+		FROM unnest($1::BIGINT[], $2::INET[], $3::TEXT[], $4::TEXT[], $5::TEXT[], $6::TEXT[], $7::TEXT[], $8::TEXT[], $9::TEXT[], $10::BOOLEAN[], $11::TIMESTAMPTZ[])
+			AS v(link_id, ip_address, user_agent, referer, country, city, device_type, os, browser, is_bot, clicked_at)
+		WHERE EXISTS (SELECT 1 FROM links l WHERE l.id = v.link_id)
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, linkID, limit, offset)
+	linkIDs := make([]int64, len(clicks))
+	ips := make([]string, len(clicks))
+	userAgents := make([]string, len(clicks))
+	referers := make([]string, len(clicks))
+	countries := make([]string, len(clicks))
+	cities := make([]string, len(clicks))
+	deviceTypes := make([]string, len(clicks))
+	systems := make([]string, len(clicks))
+	browsers := make([]string, len(clicks))
+	bots := make([]bool, len(clicks))
+	clickedAt := make([]string, len(clicks))
+
+	now := time.Now()
+	for i, click := range clicks {
+		if click.ClickedAt.IsZero() {
+			click.ClickedAt = now
+		}
+		linkIDs[i] = click.LinkID
+		ips[i] = click.IPAddress
+		userAgents[i] = click.UserAgent
+		referers[i] = click.Referer
+		countries[i] = click.Country
+		cities[i] = click.City
+		deviceTypes[i] = click.DeviceType
+		systems[i] = click.OS
+		browsers[i] = click.Browser
+		bots[i] = click.IsBot
+		clickedAt[i] = click.ClickedAt.Format(time.RFC3339Nano)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Счетчики увеличиваются в той же транзакции: батч либо записан целиком, либо может быть повторен
+	if err := incrementClicks(ctx, tx, counts); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		pq.Array(linkIDs),
+		pq.Array(ips),
+		pq.Array(userAgents),
+		pq.Array(referers),
+		pq.Array(countries),
+		pq.Array(cities),
+		pq.Array(deviceTypes),
+		pq.Array(systems),
+		pq.Array(browsers),
+		pq.Array(bots),
+		pq.Array(clickedAt),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// incrementClicks увеличивает счетчики кликов нескольких ссылок (ID ссылки -> прирост)
+func incrementClicks(ctx context.Context, tx *sql.Tx, counts map[int64]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	deltas := make([]int64, len(ids))
+	for i, id := range ids {
+		deltas[i] = counts[id]
+	}
+
+	// Блокируем строки в порядке ID, чтобы параллельные воркеры не ловили дедлоки.
+	// NO KEY UPDATE совместима с блокировками внешних ключей при вставке кликов
+	lockQuery := `SELECT id FROM links WHERE id = ANY($1) ORDER BY id FOR NO KEY UPDATE`
+	rows, err := tx.QueryContext(ctx, lockQuery, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	updateQuery := `
+		UPDATE links AS l
+		SET clicks = l.clicks + v.delta, updated_at = $3
+		FROM unnest($1::BIGINT[], $2::BIGINT[]) AS v(id, delta)
+		WHERE l.id = v.id
+	`
+	_, err = tx.ExecContext(ctx, updateQuery, pq.Array(ids), pq.Array(deltas), time.Now())
+	return err
+}
+
+func (r *linkClickRepository) GetByLinkID(ctx context.Context, filter entity.ClickFilter) ([]*entity.LinkClick, error) {
+	conditions := []string{"link_id = $1"}
+	args := []interface{}{filter.LinkID}
+
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("clicked_at >= $%d", len(args)))
+	}
+
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("clicked_at < $%d", len(args)))
+	}
+
+	// Keyset-пагинация вместо OFFSET: стоимость не растет с номером страницы
+	if filter.After != nil {
+		args = append(args, filter.After.ClickedAt, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(clicked_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT id, link_id, ip_address, user_agent, referer, country, city, device_type, os, browser, is_bot, clicked_at
+		FROM link_clicks
+		WHERE %s
+		ORDER BY clicked_at DESC, id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -61,16 +207,20 @@ func (r *linkClickRepository) GetByLinkID(ctx context.Context, linkID int64, off
 
 	for rows.Next() {
 		var click entity.LinkClick
-		var referer, country, city sql.NullString
+		var userAgent, referer, country, city, deviceType, os, browser sql.NullString
 
 		err := rows.Scan(
 			&click.ID,
 			&click.LinkID,
 			&click.IPAddress,
-			&click.UserAgent,
+			&userAgent,
 			&referer,
 			&country,
 			&city,
+			&deviceType,
+			&os,
+			&browser,
+			&click.IsBot,
 			&click.ClickedAt,
 		)
 
@@ -78,6 +228,10 @@ func (r *linkClickRepository) GetByLinkID(ctx context.Context, linkID int64, off
 			return nil, err
 		}
 
+		if userAgent.Valid {
+			click.UserAgent = userAgent.String
+		}
+
 		if referer.Valid {
 			click.Referer = referer.String
 		}
@@ -90,6 +244,10 @@ func (r *linkClickRepository) GetByLinkID(ctx context.Context, linkID int64, off
 			click.City = city.String
 		}
 
+		click.DeviceType = deviceType.String
+		click.OS = os.String
+		click.Browser = browser.String
+
 		clicks = append(clicks, &click)
 	}
 
@@ -100,136 +258,302 @@ func (r *linkClickRepository) GetByLinkID(ctx context.Context, linkID int64, off
 	return clicks, nil
 }
 
-func (r *linkClickRepository) GetStats(ctx context.Context, linkID int64, from, to time.Time) (*entity.LinkStats, error) {
+func (r *linkClickRepository) GetStats(ctx context.Context, filter entity.StatsFilter) (*entity.LinkStats, error) {
 	// Подготавливаем статистику
 	stats := &entity.LinkStats{
-		LinkID:          linkID,
-		ClicksByDate:    make(map[string]int64),
-		ClicksByCountry: make(map[string]int64),
-		ClicksByDevice:  make(map[string]int64),
-		TopReferers:     []entity.RefererStats{},
+		LinkID:           filter.LinkID,
+		ClicksByDate:     make(map[string]int64),
+		ClicksByCountry:  make(map[string]int64),
+		ClicksByDevice:   make(map[string]int64),
+		ClicksByOS:       make(map[string]int64),
+		ClicksByBrowser:  make(map[string]int64),
+		ClicksByCampaign: make(map[string]int64),
+		Timeline:         []entity.TimeBucket{},
+		TopReferers:      []entity.RefererStats{},
+		TopLinks:         []entity.LinkClickCount{},
 	}
 
-	// Получаем общее количество кликов
-	totalQuery := `
-		SELECT COUNT(*) FROM link_clicks
-		WHERE link_id = $1 AND clicked_at BETWEEN $2 AND $3
-	`
-	err := r.db.QueryRowContext(ctx, totalQuery, linkID, from, to).Scan(&stats.TotalClicks)
-	if err != nil {
-		return nil, err
+	loc := filter.Location
+	if loc == nil {
+		loc = time.UTC
 	}
 
-	// Получаем количество уникальных кликов (по IP)
-	uniqueQuery := `
-		SELECT COUNT(DISTINCT ip_address) FROM link_clicks
-		WHERE link_id = $1 AND clicked_at BETWEEN $2 AND $3
-	`
-	err = r.db.QueryRowContext(ctx, uniqueQuery, linkID, from, to).Scan(&stats.UniqueClicks)
-	if err != nil {
-		return nil, err
+	// Закрытые часы читаются из агрегатов, сырые клики - только за неполные часы по краям периода
+	// и за то, что агрегатор еще не обработал. Агрегаты выровнены по часам UTC, поэтому
+	// для поясов со смещением не в целое число часов статистика считается по сырым кликам
+	var watermark time.Time
+	if wholeHourOffset(filter.From, loc) && wholeHourOffset(filter.To, loc) {
+		var err error
+		watermark, err = rollupWatermark(ctx, r.db)
+		if err != nil {
+			return nil, err
+		}
 	}
+	ranges := splitStatsRange(filter.From, filter.To, watermark)
 
-	// Получаем статистику по дате
-	dateQuery := `
-		SELECT TO_CHAR(clicked_at, 'YYYY-MM-DD') as click_date, COUNT(*) as count
-		FROM link_clicks
-		WHERE link_id = $1 AND clicked_at BETWEEN $2 AND $3
-		GROUP BY click_date
-		ORDER BY click_date
-	`
-	dateRows, err := r.db.QueryContext(ctx, dateQuery, linkID, from, to)
+	scope, scopeID := statsScope(filter)
+
+	// Клики ботов хранятся, но по умолчанию не входят ни в одну цифру статистики
+	botFilter := ""
+	if !filter.IncludeBots {
+		botFilter = "WHERE NOT is_bot"
+	}
+
+	// Разрезы по измерениям: суточные и часовые агрегаты плюс сырые клики.
+	// Кампания берется из текущих параметров UTM ссылки
+	dimensionsQuery := fmt.Sprintf(`
+		SELECT src.link_id, l.utm_campaign, src.country, src.device_type, src.os, src.browser,
+			src.referer_host, src.is_bot, SUM(src.clicks)
+		FROM (
+			SELECT link_id, country, device_type, os, browser, referer_host, is_bot, clicks
+			FROM link_click_rollups_daily
+			WHERE %[1]s AND bucket >= $6 AND bucket < $7
+			UNION ALL
+			SELECT link_id, country, device_type, os, browser, referer_host, is_bot, clicks
+			FROM link_click_rollups_hourly
+			WHERE %[1]s AND ((bucket >= $4 AND bucket < $6) OR (bucket >= $7 AND bucket < $5))
+			UNION ALL
+			SELECT link_id, COALESCE(country, ''), COALESCE(device_type, ''), COALESCE(os, ''), COALESCE(browser, ''), %[2]s, is_bot, 1
+			FROM link_clicks
+			WHERE %[1]s AND ((clicked_at >= $2 AND clicked_at < $4) OR (clicked_at >= $5 AND clicked_at <= $3))
+		) src
+		JOIN links l ON l.id = src.link_id
+		GROUP BY src.link_id, l.utm_campaign, src.country, src.device_type, src.os, src.browser, src.referer_host, src.is_bot
+	`, scope, refererHostExpr)
+	rows, err := r.db.QueryContext(ctx, dimensionsQuery,
+		scopeID, filter.From, filter.To, ranges.hourFrom, ranges.hourTo, ranges.dayFrom, ranges.dayTo)
 	if err != nil {
 		return nil, err
 	}
-	defer dateRows.Close()
+	defer rows.Close()
 
-	for dateRows.Next() {
-		var date string
+	countries := make(map[string]int64)
+	devices := make(map[string]int64)
+	systems := make(map[string]int64)
+	browsers := make(map[string]int64)
+	referers := make(map[string]int64)
+	campaigns := make(map[string]int64)
+	links := make(map[int64]int64)
+
+	for rows.Next() {
+		var linkID int64
+		var campaign, country, device, os, browser, refererHost string
+		var isBot bool
 		var count int64
-		if err := dateRows.Scan(&date, &count); err != nil {
+		if err := rows.Scan(&linkID, &campaign, &country, &device, &os, &browser, &refererHost, &isBot, &count); err != nil {
 			return nil, err
 		}
-		stats.ClicksByDate[date] = count
+
+		if isBot {
+			stats.BotClicks += count
+			if !filter.IncludeBots {
+				continue
+			}
+		}
+
+		stats.TotalClicks += count
+		countries[orDefault(country, "Unknown")] += count
+		devices[orDefault(device, "Unknown")] += count
+		systems[orDefault(os, "Unknown")] += count
+		browsers[orDefault(browser, "Unknown")] += count
+		referers[orDefault(refererHost, "Direct")] += count
+		campaigns[orDefault(campaign, "None")] += count
+		links[linkID] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	// статистику по странам
-	countryQuery := `
-		SELECT COALESCE(country, 'Unknown') as country, COUNT(*) as count
-		FROM link_clicks
-		WHERE link_id = $1 AND clicked_at BETWEEN $2 AND $3
-		GROUP BY country
-		ORDER BY count DESC
-		LIMIT 10
-	`
-	countryRows, err := r.db.QueryContext(ctx, countryQuery, linkID, from, to)
+	stats.ClicksByCountry = topCounts(countries, 10)
+	stats.ClicksByDevice = topCounts(devices, 10)
+	stats.ClicksByOS = topCounts(systems, 10)
+	stats.ClicksByBrowser = topCounts(browsers, 10)
+	stats.ClicksByCampaign = topCounts(campaigns, 10)
+	for referer, count := range topCounts(referers, 5) {
+		stats.TopReferers = append(stats.TopReferers, entity.RefererStats{Referer: referer, Count: count})
+	}
+	sort.Slice(stats.TopReferers, func(i, j int) bool {
+		if stats.TopReferers[i].Count != stats.TopReferers[j].Count {
+			return stats.TopReferers[i].Count > stats.TopReferers[j].Count
+		}
+		return stats.TopReferers[i].Referer < stats.TopReferers[j].Referer
+	})
+	for linkID, count := range links {
+		stats.TopLinks = append(stats.TopLinks, entity.LinkClickCount{LinkID: linkID, Clicks: count})
+	}
+	sort.Slice(stats.TopLinks, func(i, j int) bool {
+		if stats.TopLinks[i].Clicks != stats.TopLinks[j].Clicks {
+			return stats.TopLinks[i].Clicks > stats.TopLinks[j].Clicks
+		}
+		return stats.TopLinks[i].LinkID < stats.TopLinks[j].LinkID
+	})
+	if len(stats.TopLinks) > 10 {
+		stats.TopLinks = stats.TopLinks[:10]
+	}
+
+	// Временной ряд по часам местного времени: суточные агрегаты выровнены по UTC и сюда не подходят.
+	// Укрупнение до нужной гранулярности делает вызывающий код
+	timelineQuery := fmt.Sprintf(`
+		SELECT date_trunc('hour', ts AT TIME ZONE $6) AS bucket, SUM(clicks)
+		FROM (
+			SELECT bucket AS ts, is_bot, clicks
+			FROM link_click_rollups_hourly
+			WHERE %[1]s AND bucket >= $4 AND bucket < $5
+			UNION ALL
+			SELECT clicked_at, is_bot, 1
+			FROM link_clicks
+			WHERE %[1]s AND ((clicked_at >= $2 AND clicked_at < $4) OR (clicked_at >= $5 AND clicked_at <= $3))
+		) src
+		%[2]s
+		GROUP BY bucket
+		ORDER BY bucket
+	`, scope, botFilter)
+	timelineRows, err := r.db.QueryContext(ctx, timelineQuery,
+		scopeID, filter.From, filter.To, ranges.hourFrom, ranges.hourTo, loc.String())
 	if err != nil {
 		return nil, err
 	}
-	defer countryRows.Close()
+	defer timelineRows.Close()
 
-	for countryRows.Next() {
-		var country string
+	for timelineRows.Next() {
+		var bucket time.Time
 		var count int64
-		if err := countryRows.Scan(&country, &count); err != nil {
+		if err := timelineRows.Scan(&bucket, &count); err != nil {
 			return nil, err
 		}
-		stats.ClicksByCountry[country] = count
-	}
-
-	// информация об устройстве из User-Agent
-	deviceQuery := `
-		SELECT
-			CASE
-				WHEN user_agent LIKE '%Mobile%' THEN 'Mobile'
-				WHEN user_agent LIKE '%Tablet%' THEN 'Tablet'
-				ELSE 'Desktop'
-			END as device,
-			COUNT(*) as count
-		FROM link_clicks
-		WHERE link_id = $1 AND clicked_at BETWEEN $2 AND $3
-		GROUP BY device
-		ORDER BY count DESC
-	`
-	deviceRows, err := r.db.QueryContext(ctx, deviceQuery, linkID, from, to)
+		// bucket - местное время без пояса; переносим его показания в loc
+		start := time.Date(bucket.Year(), bucket.Month(), bucket.Day(), bucket.Hour(), 0, 0, 0, loc)
+		stats.Timeline = append(stats.Timeline, entity.TimeBucket{Start: start, Clicks: count})
+		stats.ClicksByDate[start.Format("2006-01-02")] += count
+	}
+	if err := timelineRows.Err(); err != nil {
+		return nil, err
+	}
+
+	uniqueFrom := uniqueClicksFrom(filter.From, filter.To)
+	if uniqueFrom.After(filter.From) {
+		stats.UniqueClicksSince = &uniqueFrom
+	}
+	stats.UniqueClicks, err = r.countUnique(ctx, filter, uniqueFrom)
 	if err != nil {
 		return nil, err
 	}
-	defer deviceRows.Close()
 
-	for deviceRows.Next() {
-		var device string
-		var count int64
-		if err := deviceRows.Scan(&device, &count); err != nil {
-			return nil, err
-		}
-		stats.ClicksByDevice[device] = count
+	return stats, nil
+}
+
+func (r *linkClickRepository) GetTotals(ctx context.Context, filter entity.StatsFilter) (*entity.ClickTotals, error) {
+	// Итоги не делятся по часам местного времени, поэтому агрегаты подходят для любого пояса
+	watermark, err := rollupWatermark(ctx, r.db)
+	if err != nil {
+		return nil, err
 	}
+	ranges := splitStatsRange(filter.From, filter.To, watermark)
+	scope, scopeID := statsScope(filter)
 
-	// топ реферреров
-	refererQuery := `
-		SELECT COALESCE(referer, 'Direct') as referer, COUNT(*) as count
-		FROM link_clicks
-		WHERE link_id = $1 AND clicked_at BETWEEN $2 AND $3
-		GROUP BY referer
-		ORDER BY count DESC
-		LIMIT 5
-	`
-	refererRows, err := r.db.QueryContext(ctx, refererQuery, linkID, from, to)
+	botFilter := ""
+	if !filter.IncludeBots {
+		botFilter = "AND NOT is_bot"
+	}
+
+	totalQuery := fmt.Sprintf(`
+		SELECT COALESCE(SUM(clicks), 0)
+		FROM (
+			SELECT clicks FROM link_click_rollups_daily
+			WHERE %[1]s %[2]s AND bucket >= $6 AND bucket < $7
+			UNION ALL
+			SELECT clicks FROM link_click_rollups_hourly
+			WHERE %[1]s %[2]s AND ((bucket >= $4 AND bucket < $6) OR (bucket >= $7 AND bucket < $5))
+			UNION ALL
+			SELECT 1 FROM link_clicks
+			WHERE %[1]s %[2]s AND ((clicked_at >= $2 AND clicked_at < $4) OR (clicked_at >= $5 AND clicked_at <= $3))
+		) src
+	`, scope, botFilter)
+
+	totals := &entity.ClickTotals{}
+	err = r.db.QueryRowContext(ctx, totalQuery,
+		scopeID, filter.From, filter.To, ranges.hourFrom, ranges.hourTo, ranges.dayFrom, ranges.dayTo).Scan(&totals.TotalClicks)
 	if err != nil {
 		return nil, err
 	}
-	defer refererRows.Close()
 
-	for refererRows.Next() {
-		var refererStat entity.RefererStats
-		if err := refererRows.Scan(&refererStat.Referer, &refererStat.Count); err != nil {
-			return nil, err
-		}
-		stats.TopReferers = append(stats.TopReferers, refererStat)
+	totals.UniqueClicks, err = r.countUnique(ctx, filter, uniqueClicksFrom(filter.From, filter.To))
+	if err != nil {
+		return nil, err
 	}
 
-	return stats, nil
+	return totals, nil
+}
+
+// statsScope возвращает условие на клики одной ссылки или всех ссылок пользователя и его параметр $1
+func statsScope(filter entity.StatsFilter) (string, int64) {
+	if filter.LinkID == 0 {
+		return "link_id IN (SELECT id FROM links WHERE user_id = $1 AND deleted_at IS NULL)", filter.UserID
+	}
+	return "link_id = $1", filter.LinkID
+}
+
+// countUnique считает уникальных посетителей с from до конца периода. Они не складываются из агрегатов
+// и считаются по сырым кликам, поэтому вызывающий код ограничивает период через uniqueClicksFrom
+func (r *linkClickRepository) countUnique(ctx context.Context, filter entity.StatsFilter, from time.Time) (int64, error) {
+	scope, scopeID := statsScope(filter)
+	query := fmt.Sprintf(`
+		SELECT COUNT(DISTINCT ip_address) FROM link_clicks
+		WHERE %s AND clicked_at BETWEEN $2 AND $3
+	`, scope)
+	if !filter.IncludeBots {
+		query += " AND NOT is_bot"
+	}
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, scopeID, from, filter.To).Scan(&count)
+	return count, err
+}
+
+// uniqueClicksFrom возвращает начало периода подсчета уникальных посетителей: не раньше from
+// и не дальше maxUniqueClicksRange от to
+func uniqueClicksFrom(from, to time.Time) time.Time {
+	if limit := to.Add(-maxUniqueClicksRange); limit.After(from) {
+		return limit
+	}
+	return from
+}
+
+// wholeHourOffset сообщает, что смещение пояса в момент t - целое число часов
+func wholeHourOffset(t time.Time, loc *time.Location) bool {
+	_, offset := t.In(loc).Zone()
+	return offset%3600 == 0
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// topCounts оставляет limit значений с наибольшим количеством кликов
+func topCounts(counts map[string]int64, limit int) map[string]int64 {
+	if len(counts) <= limit {
+		return counts
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	top := make(map[string]int64, limit)
+	for _, key := range keys[:limit] {
+		top[key] = counts[key]
+	}
+	return top
 }
 
 func (r *linkClickRepository) CountByLinkID(ctx context.Context, linkID int64) (int64, error) {
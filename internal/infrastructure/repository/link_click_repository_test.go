@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniqueClicksFrom(t *testing.T) {
+	to := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success - Short period is counted whole", func(t *testing.T) {
+		from := to.AddDate(0, 0, -30)
+
+		assert.Equal(t, from, uniqueClicksFrom(from, to))
+	})
+
+	t.Run("Success - Long period is bounded", func(t *testing.T) {
+		from := to.AddDate(-1, 0, 0)
+
+		assert.Equal(t, to.Add(-maxUniqueClicksRange), uniqueClicksFrom(from, to))
+	})
+}
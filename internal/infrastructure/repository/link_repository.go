@@ -3,8 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
 )
@@ -18,10 +22,114 @@ func NewLinkRepository(db *sql.DB) repository.LinkRepository {
 	return &linkRepository{db: db}
 }
 
+// linkColumns - список колонок для выборки ссылок, порядок совпадает со scanLink.
+// Теги читаются подзапросом, поэтому таблица links в запросах не должна иметь псевдонима
+const linkColumns = `id, short_code, original_url, title, user_id, clicks, is_active, disabled_by_admin, expires_at,
+	utm_source, utm_medium, utm_campaign, utm_term, utm_content, folder_id, COALESCE(password_hash, ''),
+	max_clicks, uses, delete_when_exhausted, starts_at, fallback_url,
+	ARRAY(SELECT t.name FROM link_tags lt JOIN tags t ON t.id = lt.tag_id WHERE lt.link_id = links.id ORDER BY t.name),
+	created_at, updated_at, deleted_at`
+
+// rowScanner объединяет *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanLink читает ссылку из строки результата
+func scanLink(row rowScanner) (*entity.Link, error) {
+	var link entity.Link
+	var userID, folderID, maxClicks sql.NullInt64
+	var expiresAt, startsAt, deletedAt sql.NullTime
+
+	err := row.Scan(
+		&link.ID,
+		&link.ShortCode,
+		&link.OriginalURL,
+		&link.Title,
+		&userID,
+		&link.Clicks,
+		&link.IsActive,
+		&link.DisabledByAdmin,
+		&expiresAt,
+		&link.UTM.Source,
+		&link.UTM.Medium,
+		&link.UTM.Campaign,
+		&link.UTM.Term,
+		&link.UTM.Content,
+		&folderID,
+		&link.PasswordHash,
+		&maxClicks,
+		&link.Uses,
+		&link.DeleteWhenExhausted,
+		&startsAt,
+		&link.FallbackURL,
+		pq.Array(&link.Tags),
+		&link.CreatedAt,
+		&link.UpdatedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID.Valid {
+		link.UserID = &userID.Int64
+	}
+
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+
+	if startsAt.Valid {
+		link.StartsAt = &startsAt.Time
+	}
+
+	if folderID.Valid {
+		link.FolderID = &folderID.Int64
+	}
+
+	if maxClicks.Valid {
+		link.MaxClicks = &maxClicks.Int64
+	}
+
+	if deletedAt.Valid {
+		link.DeletedAt = &deletedAt.Time
+	}
+
+	if link.Tags == nil {
+		link.Tags = []string{}
+	}
+
+	return &link, nil
+}
+
+// scanLinks читает все ссылки из результата запроса
+func scanLinks(rows *sql.Rows) ([]*entity.Link, error) {
+	defer rows.Close()
+
+	links := make([]*entity.Link, 0)
+
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
 func (r *linkRepository) Create(ctx context.Context, link *entity.Link) error {
 	query := `
-		INSERT INTO links (short_code, original_url, user_id, clicks, expires_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO links (short_code, original_url, title, user_id, clicks, is_active, expires_at,
+			utm_source, utm_medium, utm_campaign, utm_term, utm_content, folder_id, password_hash,
+			max_clicks, delete_when_exhausted, starts_at, fallback_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14, ''), $15, $16, $17, $18, $19, $20)
 		RETURNING id
 	`
 
@@ -34,36 +142,90 @@ func (r *linkRepository) Create(ctx context.Context, link *entity.Link) error {
 		query,
 		link.ShortCode,
 		link.OriginalURL,
+		link.Title,
 		link.UserID,
 		link.Clicks,
+		link.IsActive,
 		link.ExpiresAt,
+		link.UTM.Source,
+		link.UTM.Medium,
+		link.UTM.Campaign,
+		link.UTM.Term,
+		link.UTM.Content,
+		link.FolderID,
+		link.PasswordHash,
+		link.MaxClicks,
+		link.DeleteWhenExhausted,
+		link.StartsAt,
+		link.FallbackURL,
 		link.CreatedAt,
 		link.UpdatedAt,
 	).Scan(&link.ID)
 }
 
-func (r *linkRepository) GetByShortCode(ctx context.Context, shortCode string) (*entity.Link, error) {
-	query := `
-		SELECT id, short_code, original_url, user_id, clicks, expires_at, created_at, updated_at
-		FROM links
-		WHERE short_code = $1
-	`
+func (r *linkRepository) CreateBatch(ctx context.Context, links []*entity.Link) error {
+	if len(links) == 0 {
+		return nil
+	}
 
-	var link entity.Link
-	var userID sql.NullInt64
-	var expiresAt sql.NullTime
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRowContext(ctx, query, shortCode).Scan(
-		&link.ID,
-		&link.ShortCode,
-		&link.OriginalURL,
-		&userID,
-		&link.Clicks,
-		&expiresAt,
-		&link.CreatedAt,
-		&link.UpdatedAt,
-	)
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO links (short_code, original_url, title, user_id, clicks, is_active, expires_at,
+			utm_source, utm_medium, utm_campaign, utm_term, utm_content, folder_id, password_hash,
+			max_clicks, delete_when_exhausted, starts_at, fallback_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NULLIF($14, ''), $15, $16, $17, $18, $19, $20)
+		RETURNING id
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, link := range links {
+		link.CreatedAt = now
+		link.UpdatedAt = now
+
+		err := stmt.QueryRowContext(
+			ctx,
+			link.ShortCode,
+			link.OriginalURL,
+			link.Title,
+			link.UserID,
+			link.Clicks,
+			link.IsActive,
+			link.ExpiresAt,
+			link.UTM.Source,
+			link.UTM.Medium,
+			link.UTM.Campaign,
+			link.UTM.Term,
+			link.UTM.Content,
+			link.FolderID,
+			link.PasswordHash,
+			link.MaxClicks,
+			link.DeleteWhenExhausted,
+			link.StartsAt,
+			link.FallbackURL,
+			link.CreatedAt,
+			link.UpdatedAt,
+		).Scan(&link.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert link %q: %w", link.ShortCode, err)
+		}
+	}
 
+	return tx.Commit()
+}
+
+func (r *linkRepository) GetByShortCode(ctx context.Context, shortCode string) (*entity.Link, error) {
+	query := `SELECT ` + linkColumns + ` FROM links WHERE short_code = $1 AND deleted_at IS NULL`
+
+	link, err := scanLink(r.db.QueryRowContext(ctx, query, shortCode))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -71,39 +233,13 @@ func (r *linkRepository) GetByShortCode(ctx context.Context, shortCode string) (
 		return nil, err
 	}
 
-	if userID.Valid {
-		link.UserID = &userID.Int64
-	}
-
-	if expiresAt.Valid {
-		link.ExpiresAt = &expiresAt.Time
-	}
-
-	return &link, nil
+	return link, nil
 }
 
 func (r *linkRepository) GetByID(ctx context.Context, id int64) (*entity.Link, error) {
-	query := `
-		SELECT id, short_code, original_url, user_id, clicks, expires_at, created_at, updated_at
-		FROM links
-		WHERE id = $1
-	`
-
-	var link entity.Link
-	var userID sql.NullInt64
-	var expiresAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&link.ID,
-		&link.ShortCode,
-		&link.OriginalURL,
-		&userID,
-		&link.Clicks,
-		&expiresAt,
-		&link.CreatedAt,
-		&link.UpdatedAt,
-	)
+	query := `SELECT ` + linkColumns + ` FROM links WHERE id = $1 AND deleted_at IS NULL`
 
+	link, err := scanLink(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -111,22 +247,29 @@ func (r *linkRepository) GetByID(ctx context.Context, id int64) (*entity.Link, e
 		return nil, err
 	}
 
-	if userID.Valid {
-		link.UserID = &userID.Int64
+	return link, nil
+}
+
+func (r *linkRepository) GetByIDs(ctx context.Context, ids []int64) ([]*entity.Link, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	if expiresAt.Valid {
-		link.ExpiresAt = &expiresAt.Time
+	query := `SELECT ` + linkColumns + ` FROM links WHERE id = ANY($1) AND deleted_at IS NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
 	}
 
-	return &link, nil
+	return scanLinks(rows)
 }
 
 func (r *linkRepository) GetByUserID(ctx context.Context, userID int64, offset, limit int) ([]*entity.Link, error) {
 	query := `
-		SELECT id, short_code, original_url, user_id, clicks, expires_at, created_at, updated_at
+		SELECT ` + linkColumns + `
 		FROM links
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -135,53 +278,18 @@ func (r *linkRepository) GetByUserID(ctx context.Context, userID int64, offset,
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	links := make([]*entity.Link, 0)
 
-	for rows.Next() {
-		var link entity.Link
-		var userIDNull sql.NullInt64
-		var expiresAt sql.NullTime
-
-		err := rows.Scan(
-			&link.ID,
-			&link.ShortCode,
-			&link.OriginalURL,
-			&userIDNull,
-			&link.Clicks,
-			&expiresAt,
-			&link.CreatedAt,
-			&link.UpdatedAt,
-		)
-
-		if err != nil {
-			return nil, err
-		}
-
-		if userIDNull.Valid {
-			link.UserID = &userIDNull.Int64
-		}
-
-		if expiresAt.Valid {
-			link.ExpiresAt = &expiresAt.Time
-		}
-
-		links = append(links, &link)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return links, nil
+	return scanLinks(rows)
 }
 
 func (r *linkRepository) Update(ctx context.Context, link *entity.Link) error {
 	query := `
 		UPDATE links
-		SET original_url = $1, expires_at = $2, updated_at = $3
-		WHERE id = $4
+		SET short_code = $1, original_url = $2, title = $3, is_active = $4, expires_at = $5,
+			utm_source = $6, utm_medium = $7, utm_campaign = $8, utm_term = $9, utm_content = $10,
+			folder_id = $11, password_hash = NULLIF($12, ''), starts_at = $13,
+			fallback_url = $14, updated_at = $15
+		WHERE id = $16 AND deleted_at IS NULL
 	`
 
 	link.UpdatedAt = time.Now()
@@ -189,8 +297,20 @@ func (r *linkRepository) Update(ctx context.Context, link *entity.Link) error {
 	_, err := r.db.ExecContext(
 		ctx,
 		query,
+		link.ShortCode,
 		link.OriginalURL,
+		link.Title,
+		link.IsActive,
 		link.ExpiresAt,
+		link.UTM.Source,
+		link.UTM.Medium,
+		link.UTM.Campaign,
+		link.UTM.Term,
+		link.UTM.Content,
+		link.FolderID,
+		link.PasswordHash,
+		link.StartsAt,
+		link.FallbackURL,
 		link.UpdatedAt,
 		link.ID,
 	)
@@ -198,12 +318,69 @@ func (r *linkRepository) Update(ctx context.Context, link *entity.Link) error {
 	return err
 }
 
+func (r *linkRepository) SetDisabledByAdmin(ctx context.Context, id int64, disabled bool) error {
+	query := `UPDATE links SET disabled_by_admin = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, disabled, time.Now(), id)
+	return err
+}
+
+// Delete переносит ссылку в корзину. Клики и короткий код сохраняются до очистки корзины
 func (r *linkRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM links WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	query := `UPDATE links SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	return err
+}
+
+func (r *linkRepository) GetDeletedByID(ctx context.Context, id int64) (*entity.Link, error) {
+	query := `SELECT ` + linkColumns + ` FROM links WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	link, err := scanLink(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (r *linkRepository) Restore(ctx context.Context, id int64) error {
+	query := `UPDATE links SET deleted_at = NULL, updated_at = $1 WHERE id = $2 AND deleted_at IS NOT NULL`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id)
 	return err
 }
 
+// PurgeDeleted окончательно удаляет самые старые ссылки из корзины; клики удаляются каскадно
+func (r *linkRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	query := `
+		DELETE FROM links
+		WHERE id IN (
+			SELECT id FROM links
+			WHERE deleted_at IS NOT NULL AND deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+		)
+		RETURNING short_code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := make([]string, 0)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
 func (r *linkRepository) IncrementClicks(ctx context.Context, linkID int64) error {
 	query := `
 		UPDATE links
@@ -215,61 +392,153 @@ func (r *linkRepository) IncrementClicks(ctx context.Context, linkID int64) erro
 	return err
 }
 
-func (r *linkRepository) GetExpiredLinks(ctx context.Context, before time.Time) ([]*entity.Link, error) {
+// GetFallbackURL возвращает резервный адрес ссылки, а если он не задан - резервный адрес ее владельца.
+// Для несуществующего кода возвращает пустую строку
+func (r *linkRepository) GetFallbackURL(ctx context.Context, shortCode string) (string, error) {
 	query := `
-		SELECT id, short_code, original_url, user_id, clicks, expires_at, created_at, updated_at
+		SELECT COALESCE(NULLIF(l.fallback_url, ''), u.fallback_url, '')
+		FROM links l
+		LEFT JOIN users u ON u.id = l.user_id
+		WHERE l.short_code = $1 AND l.deleted_at IS NULL
+	`
+
+	var fallbackURL string
+	err := r.db.QueryRowContext(ctx, query, shortCode).Scan(&fallbackURL)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return fallbackURL, err
+}
+
+// ConsumeUse списывает один переход в счет max_clicks. Проверка и увеличение
+// выполняются одним UPDATE, поэтому параллельные редиректы не превышают лимит
+func (r *linkRepository) ConsumeUse(ctx context.Context, linkID int64) (int64, bool, error) {
+	query := `
+		UPDATE links
+		SET uses = uses + 1
+		WHERE id = $1 AND deleted_at IS NULL AND (max_clicks IS NULL OR uses < max_clicks)
+		RETURNING uses
+	`
+
+	var uses int64
+	err := r.db.QueryRowContext(ctx, query, linkID).Scan(&uses)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uses, true, nil
+}
+
+func (r *linkRepository) GetExpiredLinks(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error) {
+	query := `
+		SELECT ` + linkColumns + `
 		FROM links
-		WHERE expires_at IS NOT NULL AND expires_at < $1
+		WHERE expires_at IS NOT NULL AND expires_at < $1 AND deleted_at IS NULL
+		ORDER BY expires_at
+		LIMIT $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, before)
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	links := make([]*entity.Link, 0)
+	return scanLinks(rows)
+}
 
-	for rows.Next() {
-		var link entity.Link
-		var userIDNull sql.NullInt64
-		var expiresAt sql.NullTime
-
-		err := rows.Scan(
-			&link.ID,
-			&link.ShortCode,
-			&link.OriginalURL,
-			&userIDNull,
-			&link.Clicks,
-			&expiresAt,
-			&link.CreatedAt,
-			&link.UpdatedAt,
-		)
+// PurgeExpired удаляет истекшие ссылки. Срок проверяется повторно: пока ссылка ждала удаления,
+// владелец мог его продлить. В режиме архива ссылки вместе с кликами и агрегатами копируются
+// в архивные таблицы в той же транзакции, иначе каскадное удаление стерло бы их статистику
+func (r *linkRepository) PurgeExpired(ctx context.Context, ids []int64, before time.Time, archive bool) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 
-		if err != nil {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lockQuery := `
+		SELECT id FROM links
+		WHERE id = ANY($1) AND expires_at < $2 AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, lockQuery, pq.Array(ids), before)
+	if err != nil {
+		return nil, err
+	}
+	purgeIDs := make([]int64, 0, len(ids))
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
 			return nil, err
 		}
+		purgeIDs = append(purgeIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(purgeIDs) == 0 {
+		return nil, nil
+	}
 
-		if userIDNull.Valid {
-			link.UserID = &userIDNull.Int64
+	if archive {
+		archiveQueries := []string{
+			`INSERT INTO links_archive (id, short_code, original_url, title, user_id, clicks, expires_at, created_at)
+			SELECT id, short_code, original_url, title, user_id, clicks, expires_at, created_at
+			FROM links WHERE id = ANY($1)`,
+			`INSERT INTO link_clicks_archive (id, link_id, ip_address, user_agent, referer, country, city,
+				device_type, os, browser, is_bot, clicked_at)
+			SELECT id, link_id, ip_address, user_agent, referer, country, city,
+				device_type, os, browser, is_bot, clicked_at
+			FROM link_clicks WHERE link_id = ANY($1)`,
+			`INSERT INTO link_click_rollups_hourly_archive (link_id, bucket, country, device_type, os, browser, referer_host, is_bot, clicks)
+			SELECT link_id, bucket, country, device_type, os, browser, referer_host, is_bot, clicks
+			FROM link_click_rollups_hourly WHERE link_id = ANY($1)`,
+			`INSERT INTO link_click_rollups_daily_archive (link_id, bucket, country, device_type, os, browser, referer_host, is_bot, clicks)
+			SELECT link_id, bucket, country, device_type, os, browser, referer_host, is_bot, clicks
+			FROM link_click_rollups_daily WHERE link_id = ANY($1)`,
 		}
-
-		if expiresAt.Valid {
-			link.ExpiresAt = &expiresAt.Time
+		for _, query := range archiveQueries {
+			if _, err := tx.ExecContext(ctx, query, pq.Array(purgeIDs)); err != nil {
+				return nil, err
+			}
 		}
-
-		links = append(links, &link)
 	}
 
+	rows, err = tx.QueryContext(ctx, `DELETE FROM links WHERE id = ANY($1) RETURNING short_code`, pq.Array(purgeIDs))
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(purgeIDs))
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	rows.Close()
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return links, nil
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return codes, nil
 }
 
 func (r *linkRepository) CountByUserID(ctx context.Context, userID int64) (int64, error) {
-	query := `SELECT COUNT(*) FROM links WHERE user_id = $1`
+	query := `SELECT COUNT(*) FROM links WHERE user_id = $1 AND deleted_at IS NULL`
 
 	var count int64
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
@@ -291,3 +560,154 @@ func (r *linkRepository) ExistsByShortCode(ctx context.Context, shortCode string
 
 	return exists, nil
 }
+
+func (r *linkRepository) FindExistingShortCodes(ctx context.Context, shortCodes []string) ([]string, error) {
+	if len(shortCodes) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT short_code FROM links WHERE short_code = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(shortCodes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var existing []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		existing = append(existing, code)
+	}
+
+	return existing, rows.Err()
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// linkFilterCondition строит WHERE-условие для фильтра ссылок
+func linkFilterCondition(filter entity.LinkFilter) (string, []interface{}) {
+	conditions := []string{"deleted_at IS NULL"}
+	if filter.Deleted {
+		conditions[0] = "deleted_at IS NOT NULL"
+	}
+	args := []interface{}{}
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	if filter.Search != "" {
+		// Слова ищутся по полнотекстовому индексу, части кода и адреса - через ILIKE
+		args = append(args, filter.Search, "%"+escapeLike(filter.Search)+"%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(search_vector @@ websearch_to_tsquery('simple', $%d) OR short_code ILIKE $%d OR original_url ILIKE $%d)",
+			len(args)-1, len(args), len(args)))
+	}
+
+	if filter.IsActive != nil {
+		// Ссылка, отключенная администратором, не активна независимо от флага владельца
+		active := "is_active AND NOT disabled_by_admin"
+		if !*filter.IsActive {
+			active = "NOT (is_active AND NOT disabled_by_admin)"
+		}
+		conditions = append(conditions, active)
+	}
+
+	if filter.Expired != nil {
+		expired := "expires_at IS NOT NULL AND expires_at < NOW()"
+		if !*filter.Expired {
+			expired = "(expires_at IS NULL OR expires_at >= NOW())"
+		}
+		conditions = append(conditions, expired)
+	}
+
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if filter.MinClicks != nil {
+		args = append(args, *filter.MinClicks)
+		conditions = append(conditions, fmt.Sprintf("clicks >= $%d", len(args)))
+	}
+
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM link_tags lt JOIN tags t ON t.id = lt.tag_id WHERE lt.link_id = links.id AND t.name = $%d)", len(args)))
+	}
+
+	if filter.FolderID != nil {
+		args = append(args, *filter.FolderID)
+		conditions = append(conditions, fmt.Sprintf("folder_id = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// linkSortColumns сопоставляет поля сортировки колонкам; значения не берутся из запроса напрямую
+var linkSortColumns = map[entity.LinkSort]string{
+	entity.LinkSortCreated: "created_at",
+	entity.LinkSortUpdated: "updated_at",
+	entity.LinkSortClicks:  "clicks",
+	entity.LinkSortDeleted: "deleted_at",
+}
+
+// linkOrder строит ORDER BY для фильтра; id делает порядок однозначным при равных значениях
+func linkOrder(filter entity.LinkFilter) string {
+	column, ok := linkSortColumns[filter.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "DESC"
+	if filter.Ascending {
+		direction = "ASC"
+	}
+	return fmt.Sprintf("%[1]s %[2]s, id %[2]s", column, direction)
+}
+
+func (r *linkRepository) List(ctx context.Context, filter entity.LinkFilter) ([]*entity.Link, error) {
+	where, args := linkFilterCondition(filter)
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM links
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, linkColumns, where, linkOrder(filter), len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanLinks(rows)
+}
+
+func (r *linkRepository) Count(ctx context.Context, filter entity.LinkFilter) (int64, error) {
+	where, args := linkFilterCondition(filter)
+	query := `SELECT COUNT(*) FROM links WHERE ` + where
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkFilterCondition(t *testing.T) {
+	t.Run("Success - Empty filter matches links outside the trash", func(t *testing.T) {
+		where, args := linkFilterCondition(entity.LinkFilter{})
+
+		assert.Equal(t, "deleted_at IS NULL", where)
+		assert.Empty(t, args)
+	})
+
+	t.Run("Success - Trash filter matches only deleted links", func(t *testing.T) {
+		userID := int64(7)
+
+		where, args := linkFilterCondition(entity.LinkFilter{UserID: &userID, Deleted: true})
+
+		assert.Equal(t, "deleted_at IS NOT NULL AND user_id = $1", where)
+		assert.Equal(t, []interface{}{userID}, args)
+	})
+
+	t.Run("Success - Placeholders follow argument order", func(t *testing.T) {
+		userID := int64(7)
+		minClicks := int64(10)
+		expired := false
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		where, args := linkFilterCondition(entity.LinkFilter{
+			UserID:      &userID,
+			Search:      "50%_off",
+			Expired:     &expired,
+			CreatedFrom: &from,
+			MinClicks:   &minClicks,
+		})
+
+		assert.Equal(t, "deleted_at IS NULL AND user_id = $1"+
+			" AND (search_vector @@ websearch_to_tsquery('simple', $2) OR short_code ILIKE $3 OR original_url ILIKE $3)"+
+			" AND (expires_at IS NULL OR expires_at >= NOW())"+
+			" AND created_at >= $4 AND clicks >= $5", where)
+		assert.Equal(t, []interface{}{userID, "50%_off", `%50\%\_off%`, from, minClicks}, args)
+	})
+
+	t.Run("Success - Active filter accounts for admin disable", func(t *testing.T) {
+		active := true
+		inactive := false
+
+		where, args := linkFilterCondition(entity.LinkFilter{IsActive: &active})
+		assert.Equal(t, "deleted_at IS NULL AND is_active AND NOT disabled_by_admin", where)
+		assert.Empty(t, args)
+
+		where, _ = linkFilterCondition(entity.LinkFilter{IsActive: &inactive})
+		assert.Equal(t, "deleted_at IS NULL AND NOT (is_active AND NOT disabled_by_admin)", where)
+	})
+}
+
+func TestLinkOrder(t *testing.T) {
+	assert.Equal(t, "created_at DESC, id DESC", linkOrder(entity.LinkFilter{}))
+	assert.Equal(t, "clicks ASC, id ASC", linkOrder(entity.LinkFilter{Sort: entity.LinkSortClicks, Ascending: true}))
+	assert.Equal(t, "updated_at DESC, id DESC", linkOrder(entity.LinkFilter{Sort: entity.LinkSortUpdated}))
+	assert.Equal(t, "deleted_at DESC, id DESC", linkOrder(entity.LinkFilter{Sort: entity.LinkSortDeleted}))
+	// Неизвестное поле не попадает в SQL
+	assert.Equal(t, "created_at DESC, id DESC", linkOrder(entity.LinkFilter{Sort: "id; DROP TABLE links"}))
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+type tagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository создает новый репозиторий тегов
+func NewTagRepository(db *sql.DB) repository.TagRepository {
+	return &tagRepository{db: db}
+}
+
+// tagColumns - список колонок для выборки тегов, порядок совпадает со scanTag
+const tagColumns = `id, user_id, name, created_at`
+
+// scanTag читает тег из строки результата
+func scanTag(row rowScanner) (*entity.Tag, error) {
+	var tag entity.Tag
+	if err := row.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	query := `
+		INSERT INTO tags (user_id, name, created_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	tag.CreatedAt = time.Now()
+
+	return r.db.QueryRowContext(ctx, query, tag.UserID, tag.Name, tag.CreatedAt).Scan(&tag.ID)
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id int64) (*entity.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags WHERE id = $1`
+
+	tag, err := scanTag(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (r *tagRepository) GetByName(ctx context.Context, userID int64, name string) (*entity.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags WHERE user_id = $1 AND name = $2`
+
+	tag, err := scanTag(r.db.QueryRowContext(ctx, query, userID, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (r *tagRepository) GetByUserID(ctx context.Context, userID int64) ([]*entity.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags WHERE user_id = $1 ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]*entity.Tag, 0)
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	query := `UPDATE tags SET name = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, tag.Name, tag.ID)
+	return err
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM tags WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *tagRepository) SetLinkTags(ctx context.Context, linkID, userID int64, names []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Недостающие теги создаются; ON CONFLICT защищает от параллельного создания того же тега
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tags (user_id, name, created_at)
+		SELECT $1, name, $3 FROM unnest($2::text[]) AS name
+		ON CONFLICT (user_id, name) DO NOTHING
+	`, userID, pq.Array(names), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM link_tags WHERE link_id = $1`, linkID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO link_tags (link_id, tag_id)
+		SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3)
+	`, linkID, userID, pq.Array(names))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
@@ -18,43 +20,57 @@ func NewUserRepository(db *sql.DB) repository.UserRepository {
 	return &userRepository{db: db}
 }
 
+// userColumns - список колонок для выборки пользователей, порядок совпадает со scanUser
+const userColumns = `id, email, password_hash, role, token_version, fallback_url, created_at, updated_at`
+
+// scanUser читает пользователя из строки результата
+func scanUser(row rowScanner) (*entity.User, error) {
+	var user entity.User
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.TokenVersion,
+		&user.FallbackURL,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 	query := `
-		INSERT INTO users (email, password_hash, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (email, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
+	if user.Role == "" {
+		user.Role = entity.RoleUser
+	}
 
 	return r.db.QueryRowContext(
 		ctx,
 		query,
 		user.Email,
 		user.PasswordHash,
+		user.Role,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&user.ID)
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
-	query := `
-		SELECT id, email, password_hash, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
-
-	var user entity.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Пользователь не найден
@@ -62,25 +78,13 @@ func (r *userRepository) GetByID(ctx context.Context, id int64) (*entity.User, e
 		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
-	query := `
-		SELECT id, email, password_hash, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-
-	var user entity.User
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Пользователь не найден
@@ -88,14 +92,14 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users
-		SET email = $1, password_hash = $2, updated_at = $3
-		WHERE id = $4
+		SET email = $1, password_hash = $2, fallback_url = $3, updated_at = $4
+		WHERE id = $5
 	`
 
 	user.UpdatedAt = time.Now()
@@ -105,12 +109,29 @@ func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 		query,
 		user.Email,
 		user.PasswordHash,
+		user.FallbackURL,
 		user.UpdatedAt,
 		user.ID,
 	)
 	return err
 }
 
+// SetRole меняет только роль: полный Update мог бы вернуть старую роль
+// или затереть пароль, измененный параллельным запросом
+func (r *userRepository) SetRole(ctx context.Context, userID int64, role entity.UserRole) (*entity.User, error) {
+	query := `UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2 RETURNING ` + userColumns
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, role, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Пользователь не найден
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id int64) error {
 	query := `DELETE FROM users WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
@@ -128,9 +149,11 @@ func (r *userRepository) GetStats(ctx context.Context, userID int64) (*entity.Us
 	query := `
 		SELECT 
 			$1::BIGINT as user_id,
-			(SELECT COUNT(*) FROM links WHERE user_id = $1) as total_links,
-			(SELECT COALESCE(SUM(clicks), 0) FROM links WHERE user_id = $1) as total_clicks,
-			(SELECT COUNT(*) FROM links WHERE user_id = $1) as active_links
+			(SELECT COUNT(*) FROM links WHERE user_id = $1 AND deleted_at IS NULL) as total_links,
+			(SELECT COALESCE(SUM(clicks), 0) FROM links WHERE user_id = $1 AND deleted_at IS NULL) as total_clicks,
+			(SELECT COUNT(*) FROM links
+				WHERE user_id = $1 AND deleted_at IS NULL AND is_active AND NOT disabled_by_admin
+					AND (expires_at IS NULL OR expires_at > NOW())) as active_links
 	`
 
 	var stats entity.UserStats
@@ -147,3 +170,104 @@ func (r *userRepository) GetStats(ctx context.Context, userID int64) (*entity.Us
 
 	return &stats, nil
 }
+
+// userFilterCondition строит WHERE-условие для фильтра пользователей
+func userFilterCondition(filter entity.UserFilter) (string, []interface{}) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+escapeLike(filter.Search)+"%")
+		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", len(args)))
+	}
+
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *userRepository) List(ctx context.Context, filter entity.UserFilter) ([]*entity.User, error) {
+	where, args := userFilterCondition(filter)
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM users
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d OFFSET $%d
+	`, userColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0)
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) Count(ctx context.Context, filter entity.UserFilter) (int64, error) {
+	where, args := userFilterCondition(filter)
+	query := `SELECT COUNT(*) FROM users WHERE ` + where
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *userRepository) GetGlobalStats(ctx context.Context) (*entity.GlobalStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users) as total_users,
+			(SELECT COUNT(*) FROM users WHERE role = 'admin') as total_admins,
+			(SELECT COUNT(*) FROM links WHERE deleted_at IS NULL) as total_links,
+			(SELECT COUNT(*) FROM links
+				WHERE deleted_at IS NULL AND is_active AND NOT disabled_by_admin
+					AND (expires_at IS NULL OR expires_at > NOW())) as active_links,
+			(SELECT COALESCE(SUM(clicks), 0) FROM links WHERE deleted_at IS NULL) as total_clicks,
+			(SELECT COUNT(*) FROM link_clicks WHERE clicked_at > NOW() - INTERVAL '24 hours' AND NOT is_bot) as clicks_last_24h
+	`
+
+	var stats entity.GlobalStats
+	err := r.db.QueryRowContext(ctx, query).Scan(
+		&stats.TotalUsers,
+		&stats.TotalAdmins,
+		&stats.TotalLinks,
+		&stats.ActiveLinks,
+		&stats.TotalClicks,
+		&stats.ClicksLast24h,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET token_version = token_version + 1 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
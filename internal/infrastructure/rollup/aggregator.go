@@ -0,0 +1,112 @@
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+// Config содержит настройки агрегатора кликов
+type Config struct {
+	Interval time.Duration // период запуска
+	// Grace - сколько ждать после конца часа перед агрегацией: клики пишутся асинхронно,
+	// а час, в который клик пришел после агрегации, пересчитывается при следующем запуске
+	Grace          time.Duration
+	MaxHoursPerRun int // ограничивает одну транзакцию при догоне истории
+}
+
+// Aggregator периодически сворачивает закрытые часы сырых кликов в часовые и суточные агрегаты.
+// Несколько экземпляров сервиса могут работать одновременно: репозиторий сериализует их в базе
+type Aggregator struct {
+	repo repository.ClickRollupRepository
+	cfg  Config
+	log  logger.Logger
+	now  func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewAggregator создает агрегатор кликов
+func NewAggregator(repo repository.ClickRollupRepository, cfg Config, log logger.Logger) *Aggregator {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Grace < 0 {
+		cfg.Grace = 0
+	}
+	if cfg.MaxHoursPerRun <= 0 {
+		cfg.MaxHoursPerRun = 24 * 7
+	}
+
+	return &Aggregator{
+		repo: repo,
+		cfg:  cfg,
+		log:  log,
+		now:  time.Now,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Start запускает фоновую агрегацию
+func (a *Aggregator) Start() {
+	go a.run()
+}
+
+// Shutdown останавливает агрегатор и дожидается завершения текущего прохода
+func (a *Aggregator) Shutdown(ctx context.Context) error {
+	a.once.Do(func() { close(a.stop) })
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("click aggregator did not stop in time: %w", ctx.Err())
+	}
+}
+
+func (a *Aggregator) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		a.RunOnce(context.Background())
+
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce агрегирует все закрытые часы; при большом отставании делает несколько транзакций подряд
+func (a *Aggregator) RunOnce(ctx context.Context) {
+	until := a.now().Add(-a.cfg.Grace).UTC().Truncate(time.Hour)
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		default:
+		}
+
+		watermark, err := a.repo.RollUp(ctx, until, a.cfg.MaxHoursPerRun)
+		if err != nil {
+			a.log.Errorf("Failed to roll up clicks: %v", err)
+			return
+		}
+		if !watermark.Before(until) {
+			return
+		}
+		a.log.Infof("Clicks rolled up to %s, catching up to %s", watermark.Format(time.RFC3339), until.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,53 @@
+package rollup
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRollupRepo struct {
+	mu        sync.Mutex
+	watermark time.Time
+	calls     []time.Time
+}
+
+func (r *fakeRollupRepo) RollUp(ctx context.Context, until time.Time, maxHours int) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, until)
+	end := r.watermark.Add(time.Duration(maxHours) * time.Hour)
+	if end.After(until) {
+		end = until
+	}
+	r.watermark = end
+	return end, nil
+}
+
+func TestAggregator_CatchesUpInSeveralTransactions(t *testing.T) {
+	repo := &fakeRollupRepo{watermark: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	a := NewAggregator(repo, Config{Grace: 10 * time.Minute, MaxHoursPerRun: 24}, logger.New("error", "text"))
+	a.now = func() time.Time { return time.Date(2024, 1, 3, 12, 5, 0, 0, time.UTC) }
+
+	a.RunOnce(context.Background())
+
+	// 12:05 минус 10 минут ожидания - закрыт только час до 11:00
+	assert.Equal(t, time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC), repo.watermark)
+	assert.Len(t, repo.calls, 3)
+}
+
+func TestAggregator_ShutdownStopsLoop(t *testing.T) {
+	repo := &fakeRollupRepo{watermark: time.Now().UTC().Truncate(time.Hour)}
+	a := NewAggregator(repo, Config{Interval: time.Hour}, logger.New("error", "text"))
+	a.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, a.Shutdown(ctx))
+	assert.NoError(t, a.Shutdown(ctx))
+}
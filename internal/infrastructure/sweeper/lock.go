@@ -0,0 +1,60 @@
+package sweeper
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Locker выбирает единственный экземпляр сервиса, который чистит ссылки
+type Locker interface {
+	// Acquire захватывает или продлевает блокировку; false - ей владеет другой экземпляр
+	Acquire(ctx context.Context) (bool, error)
+	// Release освобождает блокировку, если она принадлежит этому экземпляру
+	Release(ctx context.Context) error
+}
+
+// acquireScript продлевает свою блокировку или захватывает свободную
+var acquireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseScript удаляет блокировку, только если ей владеет этот экземпляр
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type redisLocker struct {
+	client *redis.Client
+	key    string
+	owner  string
+	ttl    time.Duration
+}
+
+// NewRedisLocker создает блокировку в Redis. owner должен различаться у экземпляров сервиса.
+// Если владелец перестал продлевать блокировку, через ttl ее захватит другой экземпляр
+func NewRedisLocker(client *redis.Client, key, owner string, ttl time.Duration) Locker {
+	return &redisLocker{client: client, key: key, owner: owner, ttl: ttl}
+}
+
+func (l *redisLocker) Acquire(ctx context.Context) (bool, error) {
+	acquired, err := acquireScript.Run(ctx, l.client, []string{l.key}, l.owner, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return acquired == 1, nil
+}
+
+func (l *redisLocker) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.client, []string{l.key}, l.owner).Err()
+}
@@ -0,0 +1,213 @@
+package sweeper
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+)
+
+// metrics публикуется через expvar под именем link_sweeper
+var metrics = expvar.NewMap("link_sweeper")
+
+// Config содержит настройки очистки истекших и удаленных ссылок
+type Config struct {
+	Interval time.Duration // период запуска
+	// Grace - сколько истекшая ссылка хранится, прежде чем ее код освободится:
+	// за это время владелец может продлить срок
+	Grace     time.Duration
+	BatchSize int  // ссылок за одну выборку и один запрос удаления
+	Archive   bool // копировать истекшие ссылки в links_archive перед удалением
+	// TrashRetention - сколько удаленная ссылка хранится в корзине до окончательного удаления
+	TrashRetention time.Duration
+}
+
+// Sweeper периодически удаляет или архивирует ссылки, истекшие дольше Grace назад,
+// и окончательно удаляет ссылки, пролежавшие в корзине дольше TrashRetention, освобождая их короткие коды.
+// При нескольких экземплярах сервиса работает только владелец блокировки
+type Sweeper struct {
+	repo   repository.LinkRepository
+	locker Locker
+	cfg    Config
+	log    logger.Logger
+	now    func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewSweeper создает очистку истекших и удаленных ссылок. Без locker считается, что экземпляр сервиса один
+func NewSweeper(repo repository.LinkRepository, locker Locker, cfg Config, log logger.Logger) *Sweeper {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	if cfg.Grace < 0 {
+		cfg.Grace = 0
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.TrashRetention < 0 {
+		cfg.TrashRetention = 0
+	}
+
+	return &Sweeper{
+		repo:   repo,
+		locker: locker,
+		cfg:    cfg,
+		log:    log,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start запускает фоновую очистку
+func (s *Sweeper) Start() {
+	go s.run()
+}
+
+// Shutdown останавливает очистку, дожидается текущего прохода и отдает блокировку другим экземплярам
+func (s *Sweeper) Shutdown(ctx context.Context) error {
+	s.once.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return fmt.Errorf("link sweeper did not stop in time: %w", ctx.Err())
+	}
+
+	if s.locker != nil {
+		if err := s.locker.Release(ctx); err != nil {
+			return fmt.Errorf("failed to release sweeper lock: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Sweeper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(context.Background())
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce удаляет истекшие ссылки и очищает корзину, пачками по BatchSize
+func (s *Sweeper) RunOnce(ctx context.Context) {
+	if s.locker != nil {
+		leader, err := s.locker.Acquire(ctx)
+		if err != nil {
+			metrics.Add("errors", 1)
+			s.log.Errorf("Failed to acquire sweeper lock: %v", err)
+			return
+		}
+		if !leader {
+			metrics.Add("runs_skipped", 1)
+			return
+		}
+	}
+
+	metrics.Add("runs", 1)
+	s.purgeExpired(ctx)
+	s.purgeTrash(ctx)
+
+	lastRun := new(expvar.Int)
+	lastRun.Set(s.now().Unix())
+	metrics.Set("last_run_unix", lastRun)
+}
+
+// stopped сообщает, что очистку попросили остановиться
+func (s *Sweeper) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// purgeExpired удаляет ссылки, истекшие дольше Grace назад
+func (s *Sweeper) purgeExpired(ctx context.Context) {
+	before := s.now().Add(-s.cfg.Grace)
+	purged := 0
+
+	for !s.stopped() {
+		links, err := s.repo.GetExpiredLinks(ctx, before, s.cfg.BatchSize)
+		if err != nil {
+			metrics.Add("errors", 1)
+			s.log.Errorf("Failed to get expired links: %v", err)
+			break
+		}
+		if len(links) == 0 {
+			break
+		}
+
+		ids := make([]int64, len(links))
+		for i, link := range links {
+			ids[i] = link.ID
+		}
+
+		codes, err := s.repo.PurgeExpired(ctx, ids, before, s.cfg.Archive)
+		if err != nil {
+			metrics.Add("errors", 1)
+			s.log.Errorf("Failed to purge expired links: %v", err)
+			break
+		}
+
+		purged += len(codes)
+		if s.cfg.Archive {
+			metrics.Add("links_archived", int64(len(codes)))
+		} else {
+			metrics.Add("links_deleted", int64(len(codes)))
+		}
+
+		if len(links) < s.cfg.BatchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		s.log.Infof("Link sweeper released %d short codes of links expired before %s", purged, before.Format(time.RFC3339))
+	}
+}
+
+// purgeTrash окончательно удаляет ссылки, пролежавшие в корзине дольше TrashRetention
+func (s *Sweeper) purgeTrash(ctx context.Context) {
+	before := s.now().Add(-s.cfg.TrashRetention)
+	purged := 0
+
+	for !s.stopped() {
+		codes, err := s.repo.PurgeDeleted(ctx, before, s.cfg.BatchSize)
+		if err != nil {
+			metrics.Add("errors", 1)
+			s.log.Errorf("Failed to purge deleted links: %v", err)
+			break
+		}
+
+		purged += len(codes)
+		metrics.Add("links_purged", int64(len(codes)))
+
+		if len(codes) < s.cfg.BatchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		s.log.Infof("Link sweeper purged %d links deleted before %s", purged, before.Format(time.RFC3339))
+	}
+}
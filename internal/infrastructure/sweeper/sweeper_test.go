@@ -0,0 +1,161 @@
+package sweeper
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLinkRepo struct {
+	repository.LinkRepository
+
+	mu      sync.Mutex
+	links   []*entity.Link
+	before  []time.Time
+	archive []bool
+	trash   []*entity.Link
+}
+
+func (r *fakeLinkRepo) GetExpiredLinks(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.before = append(r.before, before)
+	var expired []*entity.Link
+	for _, link := range r.links {
+		if len(expired) == limit {
+			break
+		}
+		if link.ExpiresAt != nil && link.ExpiresAt.Before(before) {
+			expired = append(expired, link)
+		}
+	}
+	return expired, nil
+}
+
+func (r *fakeLinkRepo) PurgeExpired(ctx context.Context, ids []int64, before time.Time, archive bool) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.archive = append(r.archive, archive)
+	purge := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		purge[id] = true
+	}
+
+	var codes []string
+	kept := r.links[:0]
+	for _, link := range r.links {
+		if purge[link.ID] {
+			codes = append(codes, link.ShortCode)
+			continue
+		}
+		kept = append(kept, link)
+	}
+	r.links = kept
+	return codes, nil
+}
+
+func (r *fakeLinkRepo) PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var codes []string
+	kept := r.trash[:0]
+	for _, link := range r.trash {
+		if len(codes) < limit && link.DeletedAt.Before(before) {
+			codes = append(codes, link.ShortCode)
+			continue
+		}
+		kept = append(kept, link)
+	}
+	r.trash = kept
+	return codes, nil
+}
+
+type fakeLocker struct {
+	leader   bool
+	released bool
+}
+
+func (l *fakeLocker) Acquire(ctx context.Context) (bool, error) { return l.leader, nil }
+
+func (l *fakeLocker) Release(ctx context.Context) error {
+	l.released = true
+	return nil
+}
+
+func linkExpiredAt(id int64, code string, expiresAt time.Time) *entity.Link {
+	return &entity.Link{ID: id, ShortCode: code, ExpiresAt: &expiresAt}
+}
+
+func TestSweeper_PurgesInBatchesAfterGrace(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := &fakeLinkRepo{links: []*entity.Link{
+		linkExpiredAt(1, "old1", now.Add(-72*time.Hour)),
+		linkExpiredAt(2, "old2", now.Add(-50*time.Hour)),
+		linkExpiredAt(3, "old3", now.Add(-49*time.Hour)),
+		// Истекла, но льготный период еще не прошел
+		linkExpiredAt(4, "recent", now.Add(-time.Hour)),
+		{ID: 5, ShortCode: "forever"},
+	}}
+	s := NewSweeper(repo, &fakeLocker{leader: true}, Config{Grace: 48 * time.Hour, BatchSize: 2, Archive: true}, logger.New("error", "text"))
+	s.now = func() time.Time { return now }
+
+	s.RunOnce(context.Background())
+
+	assert.Len(t, repo.links, 2)
+	assert.Equal(t, "recent", repo.links[0].ShortCode)
+	assert.Equal(t, "forever", repo.links[1].ShortCode)
+	assert.Equal(t, []bool{true, true}, repo.archive)
+	assert.Equal(t, now.Add(-48*time.Hour), repo.before[0])
+}
+
+func TestSweeper_PurgesTrashAfterRetention(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	deletedAt := func(ago time.Duration) *time.Time {
+		at := now.Add(-ago)
+		return &at
+	}
+	repo := &fakeLinkRepo{trash: []*entity.Link{
+		{ID: 1, ShortCode: "gone1", DeletedAt: deletedAt(40 * 24 * time.Hour)},
+		{ID: 2, ShortCode: "gone2", DeletedAt: deletedAt(31 * 24 * time.Hour)},
+		{ID: 3, ShortCode: "gone3", DeletedAt: deletedAt(30*24*time.Hour + time.Minute)},
+		{ID: 4, ShortCode: "kept", DeletedAt: deletedAt(24 * time.Hour)},
+	}}
+	s := NewSweeper(repo, nil, Config{BatchSize: 2, TrashRetention: 30 * 24 * time.Hour}, logger.New("error", "text"))
+	s.now = func() time.Time { return now }
+
+	s.RunOnce(context.Background())
+
+	assert.Len(t, repo.trash, 1)
+	assert.Equal(t, "kept", repo.trash[0].ShortCode)
+}
+
+func TestSweeper_SkipsWithoutLock(t *testing.T) {
+	now := time.Now()
+	repo := &fakeLinkRepo{links: []*entity.Link{linkExpiredAt(1, "old", now.Add(-time.Hour))}}
+	s := NewSweeper(repo, &fakeLocker{leader: false}, Config{}, logger.New("error", "text"))
+
+	s.RunOnce(context.Background())
+
+	assert.Len(t, repo.links, 1)
+	assert.Empty(t, repo.before)
+}
+
+func TestSweeper_ShutdownReleasesLock(t *testing.T) {
+	locker := &fakeLocker{leader: true}
+	s := NewSweeper(&fakeLinkRepo{}, locker, Config{Interval: time.Hour}, logger.New("error", "text"))
+	s.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, s.Shutdown(ctx))
+	assert.True(t, locker.released)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+var (
+	ErrInvalidRole = errors.New("некорректная роль пользователя")
+)
+
+// AdminUseCase defines methods for administrative business logic
+type AdminUseCase interface {
+	ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int64, error)
+	SetUserRole(ctx context.Context, userID int64, role entity.UserRole) (*entity.User, error)
+	ListLinks(ctx context.Context, filter entity.LinkFilter) ([]*entity.Link, int64, error)
+	SetLinkActive(ctx context.Context, linkID int64, active bool) (*entity.Link, error)
+	GetGlobalStats(ctx context.Context) (*entity.GlobalStats, error)
+}
+
+type adminUseCase struct {
+	userRepo repository.UserRepository
+	linkRepo repository.LinkRepository
+}
+
+// NewAdminUseCase creates a new admin use case
+func NewAdminUseCase(userRepo repository.UserRepository, linkRepo repository.LinkRepository) AdminUseCase {
+	return &adminUseCase{
+		userRepo: userRepo,
+		linkRepo: linkRepo,
+	}
+}
+
+// ListUsers возвращает страницу пользователей и их общее количество
+func (uc *adminUseCase) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int64, error) {
+	users, err := uc.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка получения списка пользователей: %w", err)
+	}
+
+	total, err := uc.userRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка подсчета пользователей: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// SetUserRole назначает пользователю роль
+func (uc *adminUseCase) SetUserRole(ctx context.Context, userID int64, role entity.UserRole) (*entity.User, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := uc.userRepo.SetRole(ctx, userID, role)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка обновления роли пользователя: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// ListLinks возвращает страницу ссылок всех пользователей и их общее количество
+func (uc *adminUseCase) ListLinks(ctx context.Context, filter entity.LinkFilter) ([]*entity.Link, int64, error) {
+	links, err := uc.linkRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка получения списка ссылок: %w", err)
+	}
+
+	total, err := uc.linkRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка подсчета ссылок: %w", err)
+	}
+
+	return links, total, nil
+}
+
+// SetLinkActive отключает любую ссылку без проверки владельца или снимает отключение.
+// Отключение хранится отдельно от is_active, поэтому владелец не может его отменить;
+// собственная приостановка ссылки владельцем при этом не меняется
+func (uc *adminUseCase) SetLinkActive(ctx context.Context, linkID int64, active bool) (*entity.Link, error) {
+	link, err := uc.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ссылки: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+
+	if link.DisabledByAdmin == !active {
+		return link, nil
+	}
+
+	if err := uc.linkRepo.SetDisabledByAdmin(ctx, linkID, !active); err != nil {
+		return nil, fmt.Errorf("ошибка обновления ссылки: %w", err)
+	}
+
+	link.DisabledByAdmin = !active
+	link.UpdatedAt = time.Now().UTC()
+
+	return link, nil
+}
+
+// GetGlobalStats возвращает статистику по всему сервису
+func (uc *adminUseCase) GetGlobalStats(ctx context.Context) (*entity.GlobalStats, error) {
+	stats, err := uc.userRepo.GetGlobalStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения общей статистики: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetRole(ctx context.Context, userID int64, role entity.UserRole) (*entity.User, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) GetStats(ctx context.Context, userID int64) (*entity.UserStats, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserStats), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filter entity.UserFilter) ([]*entity.User, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Count(ctx context.Context, filter entity.UserFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) GetGlobalStats(ctx context.Context) (*entity.GlobalStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.GlobalStats), args.Error(1)
+}
+
+func (m *MockUserRepository) IncrementTokenVersion(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// Tests
+
+func TestAdminUseCase_SetUserRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAdminUseCase(mockUserRepo, new(MockLinkRepository))
+
+		mockUserRepo.On("SetRole", ctx, int64(1), entity.RoleAdmin).Return(&entity.User{ID: 1, Role: entity.RoleAdmin}, nil)
+
+		user, err := uc.SetUserRole(ctx, 1, entity.RoleAdmin)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleAdmin, user.Role)
+		mockUserRepo.AssertExpectations(t)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - User not found", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAdminUseCase(mockUserRepo, new(MockLinkRepository))
+
+		mockUserRepo.On("SetRole", ctx, int64(1), entity.RoleAdmin).Return(nil, nil)
+
+		user, err := uc.SetUserRole(ctx, 1, entity.RoleAdmin)
+
+		assert.Equal(t, ErrUserNotFound, err)
+		assert.Nil(t, user)
+	})
+
+	t.Run("Error - Unknown role", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAdminUseCase(mockUserRepo, new(MockLinkRepository))
+
+		user, err := uc.SetUserRole(ctx, 1, entity.UserRole("root"))
+
+		assert.Equal(t, ErrInvalidRole, err)
+		assert.Nil(t, user)
+		mockUserRepo.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminUseCase_SetLinkActive(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - Disable link of another user", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewAdminUseCase(new(MockUserRepository), mockLinkRepo)
+
+		owner := int64(42)
+		mockLinkRepo.On("GetByID", ctx, int64(5)).Return(&entity.Link{ID: 5, UserID: &owner, IsActive: true}, nil)
+		mockLinkRepo.On("SetDisabledByAdmin", ctx, int64(5), true).Return(nil)
+
+		link, err := uc.SetLinkActive(ctx, 5, false)
+
+		assert.NoError(t, err)
+		assert.True(t, link.DisabledByAdmin)
+		assert.True(t, link.IsActive)
+		mockLinkRepo.AssertExpectations(t)
+		mockLinkRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Link not found", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewAdminUseCase(new(MockUserRepository), mockLinkRepo)
+
+		mockLinkRepo.On("GetByID", ctx, int64(404)).Return(nil, nil)
+
+		link, err := uc.SetLinkActive(ctx, 404, false)
+
+		assert.Equal(t, ErrLinkNotFound, err)
+		assert.Nil(t, link)
+	})
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/utils"
+)
+
+var (
+	ErrAPIKeyNotFound    = errors.New("API-ключ не найден")
+	ErrInvalidAPIKey     = errors.New("недействительный API-ключ")
+	ErrInvalidScope      = errors.New("некорректный scope API-ключа")
+	ErrInvalidAPIKeyName = errors.New("имя API-ключа должно содержать от 1 до 100 символов")
+)
+
+const (
+	// APIKeyPrefix отличает API-ключи от JWT в заголовке Authorization
+	APIKeyPrefix = "lsk_"
+
+	apiKeyBytes        = 32
+	apiKeyPrefixLength = 12
+
+	// maxAPIKeyNameLength совпадает с размером колонки api_keys.name
+	maxAPIKeyNameLength = 100
+
+	// lastUsedPrecision ограничивает частоту записи last_used_at
+	lastUsedPrecision = time.Minute
+)
+
+// APIKeyUseCase defines methods for API key business logic
+type APIKeyUseCase interface {
+	Create(ctx context.Context, userID int64, name string, scopes []entity.APIKeyScope) (*entity.APIKey, string, error)
+	List(ctx context.Context, userID int64) ([]*entity.APIKey, error)
+	Revoke(ctx context.Context, userID, keyID int64) error
+	Authenticate(ctx context.Context, rawKey string) (*entity.APIKey, *entity.User, error)
+}
+
+type apiKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   repository.UserRepository
+}
+
+// NewAPIKeyUseCase creates a new API key use case
+func NewAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository) APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Create выпускает новый API-ключ. Ключ в открытом виде возвращается только здесь,
+// в базе хранится его хеш
+func (uc *apiKeyUseCase) Create(ctx context.Context, userID int64, name string, scopes []entity.APIKeyScope) (*entity.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, "", ErrInvalidAPIKeyName
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", ErrInvalidScope
+	}
+	unique := make([]entity.APIKeyScope, 0, len(scopes))
+	seen := make(map[entity.APIKeyScope]bool, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, "", ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+
+	secret, err := utils.GenerateSecureToken(apiKeyBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка генерации API-ключа: %w", err)
+	}
+	rawKey := APIKeyPrefix + secret
+
+	key := &entity.APIKey{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  rawKey[:apiKeyPrefixLength],
+		KeyHash: utils.HashToken(rawKey),
+		Scopes:  unique,
+	}
+
+	if err := uc.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("ошибка сохранения API-ключа: %w", err)
+	}
+
+	return key, rawKey, nil
+}
+
+// List возвращает все ключи пользователя
+func (uc *apiKeyUseCase) List(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	keys, err := uc.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения API-ключей: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke отзывает ключ пользователя
+func (uc *apiKeyUseCase) Revoke(ctx context.Context, userID, keyID int64) error {
+	revoked, err := uc.apiKeyRepo.Revoke(ctx, keyID, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка отзыва API-ключа: %w", err)
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate проверяет ключ и возвращает его вместе с владельцем
+func (uc *apiKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*entity.APIKey, *entity.User, error) {
+	if !strings.HasPrefix(rawKey, APIKeyPrefix) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	key, err := uc.apiKeyRepo.GetByHash(ctx, utils.HashToken(rawKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка получения API-ключа: %w", err)
+	}
+	if key == nil || key.RevokedAt != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка получения пользователя: %w", err)
+	}
+	if user == nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedPrecision {
+		// Ошибка записи времени использования не должна блокировать запрос
+		if err := uc.apiKeyRepo.UpdateLastUsed(ctx, key.ID, now); err == nil {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, user, nil
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByUserID(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id, userID int64) (bool, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsed(ctx context.Context, id int64, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func TestAPIKeyUseCase_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - stores only the hash", func(t *testing.T) {
+		mockKeyRepo := new(MockAPIKeyRepository)
+		uc := NewAPIKeyUseCase(mockKeyRepo, new(MockUserRepository))
+
+		var stored *entity.APIKey
+		mockKeyRepo.On("Create", ctx, mock.AnythingOfType("*entity.APIKey")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*entity.APIKey) }).
+			Return(nil)
+
+		key, rawKey, err := uc.Create(ctx, 1, " CI ", []entity.APIKeyScope{entity.ScopeLinksWrite, entity.ScopeLinksWrite})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(rawKey, APIKeyPrefix))
+		assert.Equal(t, "CI", key.Name)
+		assert.Equal(t, []entity.APIKeyScope{entity.ScopeLinksWrite}, key.Scopes)
+		assert.Equal(t, utils.HashToken(rawKey), stored.KeyHash)
+		assert.True(t, strings.HasPrefix(rawKey, stored.Prefix))
+	})
+
+	t.Run("Error - unknown scope", func(t *testing.T) {
+		mockKeyRepo := new(MockAPIKeyRepository)
+		uc := NewAPIKeyUseCase(mockKeyRepo, new(MockUserRepository))
+
+		_, _, err := uc.Create(ctx, 1, "CI", []entity.APIKeyScope{"admin:all"})
+
+		assert.Equal(t, ErrInvalidScope, err)
+		mockKeyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - name longer than the column", func(t *testing.T) {
+		mockKeyRepo := new(MockAPIKeyRepository)
+		uc := NewAPIKeyUseCase(mockKeyRepo, new(MockUserRepository))
+
+		_, _, err := uc.Create(ctx, 1, strings.Repeat("я", maxAPIKeyNameLength+1), []entity.APIKeyScope{entity.ScopeLinksWrite})
+
+		assert.Equal(t, ErrInvalidAPIKeyName, err)
+		mockKeyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAPIKeyUseCase_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	user := &entity.User{ID: 1, Email: "bot@example.com", Role: entity.RoleUser}
+
+	t.Run("Success - records last use", func(t *testing.T) {
+		mockKeyRepo := new(MockAPIKeyRepository)
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAPIKeyUseCase(mockKeyRepo, mockUserRepo)
+
+		rawKey := APIKeyPrefix + "secret"
+		stored := &entity.APIKey{ID: 5, UserID: 1, Scopes: []entity.APIKeyScope{entity.ScopeLinksRead}}
+
+		mockKeyRepo.On("GetByHash", ctx, utils.HashToken(rawKey)).Return(stored, nil)
+		mockUserRepo.On("GetByID", ctx, int64(1)).Return(user, nil)
+		mockKeyRepo.On("UpdateLastUsed", ctx, int64(5), mock.AnythingOfType("time.Time")).Return(nil)
+
+		key, owner, err := uc.Authenticate(ctx, rawKey)
+
+		assert.NoError(t, err)
+		assert.Equal(t, user, owner)
+		assert.NotNil(t, key.LastUsedAt)
+		mockKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - recently used key is not touched", func(t *testing.T) {
+		mockKeyRepo := new(MockAPIKeyRepository)
+		mockUserRepo := new(MockUserRepository)
+		uc := NewAPIKeyUseCase(mockKeyRepo, mockUserRepo)
+
+		rawKey := APIKeyPrefix + "secret"
+		lastUsed := time.Now().Add(-10 * time.Second)
+		stored := &entity.APIKey{ID: 5, UserID: 1, LastUsedAt: &lastUsed}
+
+		mockKeyRepo.On("GetByHash", ctx, utils.HashToken(rawKey)).Return(stored, nil)
+		mockUserRepo.On("GetByID", ctx, int64(1)).Return(user, nil)
+
+		_, _, err := uc.Authenticate(ctx, rawKey)
+
+		assert.NoError(t, err)
+		mockKeyRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - revoked key", func(t *testing.T) {
+		mockKeyRepo := new(MockAPIKeyRepository)
+		uc := NewAPIKeyUseCase(mockKeyRepo, new(MockUserRepository))
+
+		rawKey := APIKeyPrefix + "secret"
+		revokedAt := time.Now()
+		mockKeyRepo.On("GetByHash", ctx, utils.HashToken(rawKey)).Return(&entity.APIKey{ID: 5, UserID: 1, RevokedAt: &revokedAt}, nil)
+
+		_, _, err := uc.Authenticate(ctx, rawKey)
+
+		assert.Equal(t, ErrInvalidAPIKey, err)
+	})
+}
+
+func TestAPIKeyUseCase_Revoke(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error - key of another user", func(t *testing.T) {
+		mockKeyRepo := new(MockAPIKeyRepository)
+		uc := NewAPIKeyUseCase(mockKeyRepo, new(MockUserRepository))
+
+		mockKeyRepo.On("Revoke", ctx, int64(5), int64(2)).Return(false, nil)
+
+		err := uc.Revoke(ctx, 2, 5)
+
+		assert.Equal(t, ErrAPIKeyNotFound, err)
+	})
+}
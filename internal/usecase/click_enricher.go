@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/useragent"
+)
+
+// ClickEnricher дополняет клик данными перед записью.
+// Ошибки обогащения не должны мешать редиректу, поэтому метод их не возвращает
+type ClickEnricher interface {
+	Enrich(ctx context.Context, click *entity.LinkClick)
+}
+
+// GeoResolver определяет местоположение по IP-адресу.
+// Для неизвестного адреса возвращает nil без ошибки
+type GeoResolver interface {
+	Lookup(ip string) (*entity.GeoLocation, error)
+}
+
+type geoEnricher struct {
+	resolver GeoResolver
+}
+
+// NewGeoEnricher creates a click enricher that fills Country and City from the visitor's IP
+func NewGeoEnricher(resolver GeoResolver) ClickEnricher {
+	return &geoEnricher{resolver: resolver}
+}
+
+// Enrich заполняет страну и город клика, если они еще не заданы
+func (e *geoEnricher) Enrich(ctx context.Context, click *entity.LinkClick) {
+	if click.IPAddress == "" || (click.Country != "" && click.City != "") {
+		return
+	}
+
+	location, err := e.resolver.Lookup(click.IPAddress)
+	if err != nil || location == nil {
+		return
+	}
+
+	if click.Country == "" {
+		click.Country = location.Country
+	}
+	if click.City == "" {
+		click.City = location.City
+	}
+}
+
+type userAgentEnricher struct {
+	detector *useragent.BotDetector
+}
+
+// NewUserAgentEnricher creates a click enricher that classifies the User-Agent into device type, OS, browser and bot flag.
+// If detector is nil, only the built-in bot signatures are used
+func NewUserAgentEnricher(detector *useragent.BotDetector) ClickEnricher {
+	if detector == nil {
+		detector = useragent.NewBotDetector(nil, nil)
+	}
+	return &userAgentEnricher{detector: detector}
+}
+
+// Enrich разбирает User-Agent клика
+func (e *userAgentEnricher) Enrich(ctx context.Context, click *entity.LinkClick) {
+	info := e.detector.Parse(click.UserAgent)
+
+	click.DeviceType = info.DeviceType
+	click.OS = info.OS
+	click.Browser = info.Browser
+	click.IsBot = info.IsBot
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+// DashboardUseCase defines methods for account-wide analytics
+type DashboardUseCase interface {
+	GetDashboard(ctx context.Context, userID int64, query StatsQuery) (*entity.Dashboard, error)
+}
+
+type dashboardUseCase struct {
+	userRepo      repository.UserRepository
+	linkRepo      repository.LinkRepository
+	linkClickRepo repository.LinkClickRepository
+}
+
+// NewDashboardUseCase creates a new dashboard use case
+func NewDashboardUseCase(userRepo repository.UserRepository, linkRepo repository.LinkRepository, linkClickRepo repository.LinkClickRepository) DashboardUseCase {
+	return &dashboardUseCase{
+		userRepo:      userRepo,
+		linkRepo:      linkRepo,
+		linkClickRepo: linkClickRepo,
+	}
+}
+
+// GetDashboard собирает аналитику по всем ссылкам пользователя за период
+// и сравнивает ее с предыдущим периодом той же длины
+func (uc *dashboardUseCase) GetDashboard(ctx context.Context, userID int64, query StatsQuery) (*entity.Dashboard, error) {
+	if err := normalizeStatsQuery(&query); err != nil {
+		return nil, err
+	}
+
+	userStats, err := uc.userRepo.GetStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	current, err := uc.linkClickRepo.GetStats(ctx, entity.StatsFilter{
+		UserID:      userID,
+		From:        query.From,
+		To:          query.To,
+		IncludeBots: query.IncludeBots,
+		Location:    query.Location,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	// Для предыдущего периода нужны только итоги.
+	// Границы периодов не пересекаются: конец предыдущего на микросекунду раньше начала текущего
+	previous, err := uc.linkClickRepo.GetTotals(ctx, entity.StatsFilter{
+		UserID:      userID,
+		From:        query.From.Add(-query.To.Sub(query.From)),
+		To:          query.From.Add(-time.Microsecond),
+		IncludeBots: query.IncludeBots,
+		Location:    query.Location,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous period stats: %w", err)
+	}
+
+	ids := make([]int64, len(current.TopLinks))
+	for i, item := range current.TopLinks {
+		ids[i] = item.LinkID
+	}
+	links, err := uc.linkRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top links: %w", err)
+	}
+	byID := make(map[int64]*entity.Link, len(links))
+	for _, link := range links {
+		byID[link.ID] = link
+	}
+
+	topLinks := make([]entity.TopLink, 0, len(current.TopLinks))
+	for _, item := range current.TopLinks {
+		// Ссылка могла быть удалена после агрегации
+		link, ok := byID[item.LinkID]
+		if !ok {
+			continue
+		}
+		topLinks = append(topLinks, entity.TopLink{Link: link, Clicks: item.Clicks})
+	}
+
+	return &entity.Dashboard{
+		From:         query.From,
+		To:           query.To,
+		Granularity:  query.Granularity,
+		Timezone:     query.Location.String(),
+		TotalLinks:   userStats.TotalLinks,
+		ActiveLinks:  userStats.ActiveLinks,
+		Clicks:       comparePeriods(current.TotalClicks, previous.TotalClicks),
+		UniqueClicks: comparePeriods(current.UniqueClicks, previous.UniqueClicks),
+		Timeline:     fillTimeline(current.Timeline, query.From, query.To, query.Granularity, query.Location),
+		TopLinks:     topLinks,
+		TopCampaigns: current.ClicksByCampaign,
+		TopCountries: current.ClicksByCountry,
+		TopReferers:  current.TopReferers,
+	}, nil
+}
+
+// comparePeriods считает прирост в процентах с точностью до десятых
+func comparePeriods(current, previous int64) entity.PeriodComparison {
+	comparison := entity.PeriodComparison{Current: current, Previous: previous}
+	if previous > 0 {
+		growth := math.Round(float64(current-previous)/float64(previous)*1000) / 10
+		comparison.GrowthPercent = &growth
+	}
+	return comparison
+}
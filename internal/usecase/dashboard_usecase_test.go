@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDashboardUseCase_GetDashboard(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success - compares with previous period and skips deleted links", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		linkRepo := new(MockLinkRepository)
+		clickRepo := new(MockLinkClickRepository)
+		uc := NewDashboardUseCase(userRepo, linkRepo, clickRepo)
+
+		userRepo.On("GetStats", ctx, int64(1)).Return(&entity.UserStats{UserID: 1, TotalLinks: 3, ActiveLinks: 2}, nil)
+		clickRepo.On("GetStats", ctx, entity.StatsFilter{
+			UserID:   1,
+			From:     from,
+			To:       to,
+			Location: time.UTC,
+		}).Return(&entity.LinkStats{
+			TotalClicks:      15,
+			UniqueClicks:     4,
+			ClicksByCountry:  map[string]int64{"DE": 15},
+			ClicksByCampaign: map[string]int64{"spring": 12, "None": 3},
+			Timeline:         []entity.TimeBucket{{Start: from.Add(time.Hour), Clicks: 15}},
+			TopLinks:         []entity.LinkClickCount{{LinkID: 10, Clicks: 12}, {LinkID: 11, Clicks: 3}},
+		}, nil)
+		clickRepo.On("GetTotals", ctx, entity.StatsFilter{
+			UserID:   1,
+			From:     from.Add(-48 * time.Hour),
+			To:       from.Add(-time.Microsecond),
+			Location: time.UTC,
+		}).Return(&entity.ClickTotals{TotalClicks: 10}, nil)
+		linkRepo.On("GetByIDs", ctx, []int64{10, 11}).Return([]*entity.Link{{ID: 10, ShortCode: "abc"}}, nil)
+
+		dashboard, err := uc.GetDashboard(ctx, 1, StatsQuery{From: from, To: to})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.GranularityDay, dashboard.Granularity)
+		assert.Equal(t, "UTC", dashboard.Timezone)
+		assert.Equal(t, int64(3), dashboard.TotalLinks)
+		assert.Equal(t, int64(2), dashboard.ActiveLinks)
+		assert.Equal(t, int64(15), dashboard.Clicks.Current)
+		assert.Equal(t, int64(10), dashboard.Clicks.Previous)
+		assert.Equal(t, 50.0, *dashboard.Clicks.GrowthPercent)
+		// Без кликов в предыдущем периоде прирост не определен
+		assert.Nil(t, dashboard.UniqueClicks.GrowthPercent)
+		assert.Equal(t, []entity.TimeBucket{
+			{Start: from, Clicks: 15},
+			{Start: from.AddDate(0, 0, 1), Clicks: 0},
+			{Start: to, Clicks: 0},
+		}, dashboard.Timeline)
+		assert.Equal(t, map[string]int64{"spring": 12, "None": 3}, dashboard.TopCampaigns)
+		assert.Len(t, dashboard.TopLinks, 1)
+		assert.Equal(t, int64(10), dashboard.TopLinks[0].Link.ID)
+		assert.Equal(t, int64(12), dashboard.TopLinks[0].Clicks)
+		userRepo.AssertExpectations(t)
+		linkRepo.AssertExpectations(t)
+		clickRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid granularity", func(t *testing.T) {
+		uc := NewDashboardUseCase(new(MockUserRepository), new(MockLinkRepository), new(MockLinkClickRepository))
+
+		dashboard, err := uc.GetDashboard(ctx, 1, StatsQuery{From: from, To: to, Granularity: "year"})
+
+		assert.ErrorIs(t, err, ErrInvalidGranularity)
+		assert.Nil(t, dashboard)
+	})
+
+	t.Run("Error - previous period stats fail", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		clickRepo := new(MockLinkClickRepository)
+		uc := NewDashboardUseCase(userRepo, new(MockLinkRepository), clickRepo)
+
+		userRepo.On("GetStats", ctx, int64(1)).Return(&entity.UserStats{UserID: 1}, nil)
+		clickRepo.On("GetStats", ctx, mock.MatchedBy(func(f entity.StatsFilter) bool { return f.From.Equal(from) })).
+			Return(&entity.LinkStats{}, nil)
+		clickRepo.On("GetTotals", ctx, mock.MatchedBy(func(f entity.StatsFilter) bool { return f.From.Before(from) })).
+			Return(nil, assert.AnError)
+
+		dashboard, err := uc.GetDashboard(ctx, 1, StatsQuery{From: from, To: to})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, dashboard)
+	})
+}
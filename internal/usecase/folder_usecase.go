@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+var (
+	ErrFolderNotFound    = errors.New("folder not found")
+	ErrFolderExists      = errors.New("folder with this name already exists")
+	ErrInvalidFolderName = errors.New("folder name must be 1 to 100 characters")
+)
+
+// maxFolderNameLength совпадает с размером колонки folders.name
+const maxFolderNameLength = 100
+
+// FolderUseCase defines methods for folder business logic
+type FolderUseCase interface {
+	List(ctx context.Context, userID int64) ([]*entity.Folder, error)
+	Create(ctx context.Context, userID int64, name string) (*entity.Folder, error)
+	Rename(ctx context.Context, userID, folderID int64, name string) (*entity.Folder, error)
+	Delete(ctx context.Context, userID, folderID int64) error
+	MoveLink(ctx context.Context, userID, linkID int64, folderID *int64) (*entity.Link, error)
+}
+
+type folderUseCase struct {
+	folderRepo repository.FolderRepository
+	linkRepo   repository.LinkRepository
+}
+
+// NewFolderUseCase creates a new folder use case
+func NewFolderUseCase(folderRepo repository.FolderRepository, linkRepo repository.LinkRepository) FolderUseCase {
+	return &folderUseCase{
+		folderRepo: folderRepo,
+		linkRepo:   linkRepo,
+	}
+}
+
+// List возвращает папки пользователя по алфавиту
+func (uc *folderUseCase) List(ctx context.Context, userID int64) ([]*entity.Folder, error) {
+	folders, err := uc.folderRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+	return folders, nil
+}
+
+// Create создает папку с уникальным для пользователя именем
+func (uc *folderUseCase) Create(ctx context.Context, userID int64, name string) (*entity.Folder, error) {
+	name, err := uc.checkName(ctx, userID, 0, name)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &entity.Folder{UserID: userID, Name: name}
+	if err := uc.folderRepo.Create(ctx, folder); err != nil {
+		return nil, fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	return folder, nil
+}
+
+// Rename переименовывает папку пользователя
+func (uc *folderUseCase) Rename(ctx context.Context, userID, folderID int64, name string) (*entity.Folder, error) {
+	folder, err := uc.getOwned(ctx, userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err = uc.checkName(ctx, userID, folderID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+	folder.UpdatedAt = time.Now()
+	if err := uc.folderRepo.Update(ctx, folder); err != nil {
+		return nil, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	return folder, nil
+}
+
+// Delete удаляет папку пользователя. Ссылки из нее остаются без папки
+func (uc *folderUseCase) Delete(ctx context.Context, userID, folderID int64) error {
+	if _, err := uc.getOwned(ctx, userID, folderID); err != nil {
+		return err
+	}
+
+	if err := uc.folderRepo.Delete(ctx, folderID); err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+
+	return nil
+}
+
+// MoveLink перемещает ссылку в папку; nil убирает ссылку из папки
+func (uc *folderUseCase) MoveLink(ctx context.Context, userID, linkID int64, folderID *int64) (*entity.Link, error) {
+	link, err := getOwnedLink(ctx, uc.linkRepo, linkID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if folderID != nil {
+		if _, err := uc.getOwned(ctx, userID, *folderID); err != nil {
+			return nil, err
+		}
+	}
+
+	link.FolderID = folderID
+	link.UpdatedAt = time.Now().UTC()
+	if err := uc.linkRepo.Update(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to update link: %w", err)
+	}
+
+	return link, nil
+}
+
+// getOwned возвращает папку, если она принадлежит пользователю.
+// Чужая папка неотличима от несуществующей
+func (uc *folderUseCase) getOwned(ctx context.Context, userID, folderID int64) (*entity.Folder, error) {
+	folder, err := uc.folderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+	if folder == nil || folder.UserID != userID {
+		return nil, ErrFolderNotFound
+	}
+	return folder, nil
+}
+
+// checkName нормализует имя папки и проверяет, что оно не занято другой папкой пользователя
+func (uc *folderUseCase) checkName(ctx context.Context, userID, folderID int64, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxFolderNameLength {
+		return "", ErrInvalidFolderName
+	}
+
+	existing, err := uc.folderRepo.GetByName(ctx, userID, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to check folder name: %w", err)
+	}
+	if existing != nil && existing.ID != folderID {
+		return "", ErrFolderExists
+	}
+
+	return name, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFolderRepository is a mock implementation of FolderRepository
+type MockFolderRepository struct {
+	mock.Mock
+}
+
+func (m *MockFolderRepository) Create(ctx context.Context, folder *entity.Folder) error {
+	args := m.Called(ctx, folder)
+	return args.Error(0)
+}
+
+func (m *MockFolderRepository) GetByID(ctx context.Context, id int64) (*entity.Folder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) GetByName(ctx context.Context, userID int64, name string) (*entity.Folder, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) GetByUserID(ctx context.Context, userID int64) ([]*entity.Folder, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Folder), args.Error(1)
+}
+
+func (m *MockFolderRepository) Update(ctx context.Context, folder *entity.Folder) error {
+	args := m.Called(ctx, folder)
+	return args.Error(0)
+}
+
+func (m *MockFolderRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestFolderUseCase_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - name is trimmed", func(t *testing.T) {
+		folderRepo := new(MockFolderRepository)
+		uc := NewFolderUseCase(folderRepo, new(MockLinkRepository))
+
+		folderRepo.On("GetByName", ctx, int64(1), "Marketing").Return(nil, nil)
+		folderRepo.On("Create", ctx, mock.AnythingOfType("*entity.Folder")).Return(nil)
+
+		folder, err := uc.Create(ctx, 1, "  Marketing ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Marketing", folder.Name)
+		assert.Equal(t, int64(1), folder.UserID)
+		folderRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - name is taken", func(t *testing.T) {
+		folderRepo := new(MockFolderRepository)
+		uc := NewFolderUseCase(folderRepo, new(MockLinkRepository))
+
+		folderRepo.On("GetByName", ctx, int64(1), "Marketing").Return(&entity.Folder{ID: 5, UserID: 1, Name: "Marketing"}, nil)
+
+		folder, err := uc.Create(ctx, 1, "Marketing")
+
+		assert.ErrorIs(t, err, ErrFolderExists)
+		assert.Nil(t, folder)
+	})
+
+	t.Run("Error - empty name", func(t *testing.T) {
+		uc := NewFolderUseCase(new(MockFolderRepository), new(MockLinkRepository))
+
+		_, err := uc.Create(ctx, 1, "   ")
+
+		assert.ErrorIs(t, err, ErrInvalidFolderName)
+	})
+}
+
+func TestFolderUseCase_MoveLink(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	folderID := int64(5)
+
+	t.Run("Success - link is moved to folder", func(t *testing.T) {
+		folderRepo := new(MockFolderRepository)
+		linkRepo := new(MockLinkRepository)
+		uc := NewFolderUseCase(folderRepo, linkRepo)
+
+		linkRepo.On("GetByID", ctx, int64(10)).Return(&entity.Link{ID: 10, UserID: &userID}, nil)
+		folderRepo.On("GetByID", ctx, folderID).Return(&entity.Folder{ID: folderID, UserID: userID}, nil)
+		linkRepo.On("Update", ctx, mock.MatchedBy(func(l *entity.Link) bool {
+			return l.FolderID != nil && *l.FolderID == folderID
+		})).Return(nil)
+
+		link, err := uc.MoveLink(ctx, userID, 10, &folderID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, folderID, *link.FolderID)
+		linkRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - folder of another user", func(t *testing.T) {
+		folderRepo := new(MockFolderRepository)
+		linkRepo := new(MockLinkRepository)
+		uc := NewFolderUseCase(folderRepo, linkRepo)
+
+		linkRepo.On("GetByID", ctx, int64(10)).Return(&entity.Link{ID: 10, UserID: &userID}, nil)
+		folderRepo.On("GetByID", ctx, folderID).Return(&entity.Folder{ID: folderID, UserID: 2}, nil)
+
+		link, err := uc.MoveLink(ctx, userID, 10, &folderID)
+
+		assert.ErrorIs(t, err, ErrFolderNotFound)
+		assert.Nil(t, link)
+		linkRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
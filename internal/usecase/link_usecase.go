@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
@@ -13,42 +16,154 @@ import (
 )
 
 var (
-	ErrInvalidURL       = errors.New("invalid URL")
-	ErrLinkNotFound     = errors.New("link not found")
-	ErrLinkExpired      = errors.New("link has expired")
-	ErrLinkInactive     = errors.New("link is inactive")
-	ErrUnauthorized     = errors.New("unauthorized")
-	ErrShortCodeExists  = errors.New("short code already exists")
-	ErrExpirationInPast = errors.New("expiration date cannot be in the past")
-	ErrExpiration       = errors.New("date expired")
+	ErrInvalidURL          = errors.New("invalid URL")
+	ErrLinkNotFound        = errors.New("link not found")
+	ErrLinkExpired         = errors.New("link has expired")
+	ErrLinkInactive        = errors.New("link is inactive")
+	ErrLinkDisabled        = errors.New("link is disabled by an administrator")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrShortCodeExists     = errors.New("short code already exists")
+	ErrInvalidShortCode    = errors.New("invalid short code")
+	ErrExpirationInPast    = errors.New("expiration date cannot be in the past")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrInvalidGranularity  = errors.New("granularity must be one of hour, day, week, month")
+	ErrTimelineTooLong     = errors.New("requested period has too many buckets for this granularity")
+	ErrInvalidUTM          = errors.New("UTM parameters must be at most 255 characters")
+	ErrInvalidTitle        = errors.New("title must be at most 255 characters")
+	ErrInvalidSort         = errors.New("sort must be one of created, updated, clicks")
+	ErrInvalidLinkPassword = errors.New("link password must be 4 to 72 bytes long")
+	ErrPasswordRequired    = errors.New("link is password protected")
+	ErrWrongPassword       = errors.New("wrong link password")
+	ErrInvalidMaxClicks    = errors.New("max_clicks must be positive and is required for delete_when_exhausted")
+	ErrLinkExhausted       = errors.New("link has reached its click limit")
+	ErrLinkNotStarted      = errors.New("link is not available yet")
+	ErrInvalidWindow       = errors.New("starts_at must be before expires_at")
+	ErrInvalidFallbackURL  = errors.New("fallback_url must be an absolute http(s) URL")
 )
 
 // LinkUseCase defines methods for link business logic
 type LinkUseCase interface {
-	CreateLink(ctx context.Context, originalURL string, userID *int64, customCode string, expiresAt *time.Time) (*entity.Link, error)
+	CreateLink(ctx context.Context, userID *int64, input LinkInput) (*entity.Link, error)
+	CreateLinks(ctx context.Context, userID *int64, inputs []LinkInput, atomic bool) ([]BatchLinkResult, error)
 	GetLinkByShortCode(ctx context.Context, shortCode string) (*entity.Link, error)
-	GetUserLinks(ctx context.Context, userID int64, offset, limit int) ([]*entity.Link, error)
-	UpdateLink(ctx context.Context, linkID int64, userID int64, expiresAt *time.Time) error
+	GetUserLinks(ctx context.Context, userID int64, filter entity.LinkFilter) ([]*entity.Link, int64, error)
+	ExportLinks(ctx context.Context, userID int64, visit func(*entity.Link) error) error
+	UpdateLink(ctx context.Context, linkID int64, userID int64, update LinkUpdate) (*entity.Link, error)
+	SetLinkActive(ctx context.Context, linkID int64, userID int64, active bool) error
 	DeleteLink(ctx context.Context, linkID int64, userID int64) error
-	RecordClick(ctx context.Context, shortCode, ipAddress, userAgent, referer string) (*entity.Link, error)
-	GetLinkStats(ctx context.Context, linkID int64, userID int64, from, to time.Time) (*entity.LinkStats, error)
+	GetTrash(ctx context.Context, userID int64, filter entity.LinkFilter) ([]*entity.Link, int64, error)
+	RestoreLink(ctx context.Context, linkID int64, userID int64) (*entity.Link, error)
+	RecordClick(ctx context.Context, shortCode, password, ipAddress, userAgent, referer string) (*entity.Link, error)
+	GetLinkStats(ctx context.Context, linkID int64, userID int64, query StatsQuery) (*entity.LinkStats, error)
+	ListClicks(ctx context.Context, linkID int64, userID int64, query ClickQuery) (*ClickPage, error)
 	GetLink(ctx context.Context, linkID int64, userID int64) (*entity.Link, error)
+	GetFallbackURL(ctx context.Context, shortCode string) (string, error)
+}
+
+// LinkUpdate описывает изменения ссылки. Поля со значением nil остаются без изменений
+type LinkUpdate struct {
+	OriginalURL    *string
+	ShortCode      *string
+	Title          *string // пустая строка убирает название
+	ExpiresAt      *time.Time
+	ClearExpiresAt bool // снять срок действия; имеет приоритет над ExpiresAt
+	StartsAt       *time.Time
+	ClearStartsAt  bool // открыть ссылку сразу; имеет приоритет над StartsAt
+	IsActive       *bool
+	Password       *string // пустая строка снимает пароль
+	FallbackURL    *string // пустая строка убирает резервный адрес
+}
+
+// LinkInput описывает новую ссылку
+type LinkInput struct {
+	OriginalURL string
+	CustomCode  string
+	Title       string
+	ExpiresAt   *time.Time
+	StartsAt    *time.Time // до этого момента ссылка не открывается; nil - открывается сразу
+	UTM         entity.UTM // дописывается к OriginalURL, заменяя одноименные параметры
+	Password    string     // пустая строка - ссылка без пароля
+	FallbackURL string     // куда вести после истечения или отключения ссылки
+	MaxClicks   *int64     // nil - без ограничения переходов
+	// DeleteWhenExhausted удаляет ссылку после перехода, исчерпавшего MaxClicks
+	DeleteWhenExhausted bool
+	// Tags проверяются вместе с остальными полями, но назначаются созданной ссылке
+	// вызывающим кодом через TagUseCase.SetLinkTags
+	Tags []string
+
+	passwordHash string // заполняется prepareLinkInput
+}
+
+// BatchLinkResult содержит результат создания одной ссылки из пакета.
+// Link заполнен только для созданных ссылок, Err - для отклоненных
+type BatchLinkResult struct {
+	Link *entity.Link
+	Err  error
+}
+
+// StatsQuery описывает запрос статистики ссылки
+type StatsQuery struct {
+	From        time.Time
+	To          time.Time
+	IncludeBots bool               // учитывать клики ботов во всех цифрах
+	Granularity entity.Granularity // по умолчанию день
+	Location    *time.Location     // часовой пояс интервалов; по умолчанию UTC
+}
+
+// maxTitleLength совпадает с размером колонки links.title
+const maxTitleLength = 255
+
+// Границы длины пароля ссылки; bcrypt не учитывает байты после 72-го
+const (
+	minLinkPasswordLength = 4
+	maxLinkPasswordLength = 72
+)
+
+// maxTimelineBuckets ограничивает размер временного ряда, например часы за год - 8760
+const maxTimelineBuckets = 10000
+
+// ClickQuery описывает запрос страницы журнала кликов
+type ClickQuery struct {
+	From   *time.Time
+	To     *time.Time
+	Cursor string // непрозрачный курсор из ClickPage.NextCursor
+	Limit  int
+}
+
+// ClickPage - страница журнала кликов. IPAddress кликов заменен хешем
+type ClickPage struct {
+	Clicks     []*entity.LinkClick
+	NextCursor string // пустой, если страниц больше нет
+}
+
+// ClickQueue принимает клики для асинхронной записи в базу
+type ClickQueue interface {
+	Enqueue(ctx context.Context, click *entity.LinkClick) error
 }
 
 type linkUseCase struct {
 	linkRepo      repository.LinkRepository
 	linkClickRepo repository.LinkClickRepository
+	clickQueue    ClickQueue
 	shortURLLen   int
 	baseURL       string
+	ipHashSalt    string
+	enrichers     []ClickEnricher
 }
 
-// NewLinkUseCase creates a new link use case
-func NewLinkUseCase(linkRepo repository.LinkRepository, linkClickRepo repository.LinkClickRepository, shortURLLen int, baseURL string) LinkUseCase {
+// NewLinkUseCase creates a new link use case.
+// If clickQueue is nil, clicks are written synchronously during the redirect.
+// ipHashSalt is used to hash visitor IPs returned by the click log.
+// Enrichers are applied to every click, in order, before it is recorded.
+func NewLinkUseCase(linkRepo repository.LinkRepository, linkClickRepo repository.LinkClickRepository, clickQueue ClickQueue, shortURLLen int, baseURL, ipHashSalt string, enrichers ...ClickEnricher) LinkUseCase {
 	return &linkUseCase{
 		linkRepo:      linkRepo,
 		linkClickRepo: linkClickRepo,
+		clickQueue:    clickQueue,
 		shortURLLen:   shortURLLen,
 		baseURL:       baseURL,
+		ipHashSalt:    ipHashSalt,
+		enrichers:     enrichers,
 	}
 }
 
@@ -62,25 +177,22 @@ func toUTC(t *time.Time) *time.Time {
 }
 
 // CreateLink создает новую короткую ссылку
-func (uc *linkUseCase) CreateLink(ctx context.Context, originalURL string, userID *int64, customCode string, expiresAt *time.Time) (*entity.Link, error) {
-	if !validator.IsValidURL(originalURL) {
-		return nil, ErrInvalidURL
-	}
-
-	if expiresAt != nil && expiresAt.UTC().Before(time.Now().UTC()) {
-		return nil, ErrExpirationInPast
+func (uc *linkUseCase) CreateLink(ctx context.Context, userID *int64, input LinkInput) (*entity.Link, error) {
+	input, err := prepareLinkInput(input)
+	if err != nil {
+		return nil, err
 	}
 
 	var shortCode string
-	if customCode != "" {
-		exists, err := uc.linkRepo.ExistsByShortCode(ctx, customCode)
+	if input.CustomCode != "" {
+		exists, err := uc.linkRepo.ExistsByShortCode(ctx, input.CustomCode)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check short code existence: %w", err)
 		}
 		if exists {
 			return nil, ErrShortCodeExists
 		}
-		shortCode = customCode
+		shortCode = input.CustomCode
 	} else {
 		for {
 			shortCode = utils.GenerateShortCode(uc.shortURLLen)
@@ -94,15 +206,7 @@ func (uc *linkUseCase) CreateLink(ctx context.Context, originalURL string, userI
 		}
 	}
 
-	now := time.Now()
-	link := &entity.Link{
-		ShortCode:   shortCode,
-		OriginalURL: originalURL,
-		UserID:      userID,
-		ExpiresAt:   expiresAt,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
+	link := newLink(input, shortCode, userID)
 
 	if err := uc.linkRepo.Create(ctx, link); err != nil {
 		return nil, fmt.Errorf("failed to create link: %w", err)
@@ -111,7 +215,239 @@ func (uc *linkUseCase) CreateLink(ctx context.Context, originalURL string, userI
 	return link, nil
 }
 
-// GetLinkByShortCode получает ссылку по короткому коду с проверкой активности и срока действия
+// CreateLinks создает пакет ссылок. Занятость кодов проверяется одним запросом на весь пакет.
+// В режиме atomic ссылки создаются в одной транзакции и только если все элементы корректны;
+// иначе корректные элементы создаются, а ошибки возвращаются по каждому элементу
+func (uc *linkUseCase) CreateLinks(ctx context.Context, userID *int64, inputs []LinkInput, atomic bool) ([]BatchLinkResult, error) {
+	results := make([]BatchLinkResult, len(inputs))
+	prepared := make([]LinkInput, len(inputs))
+
+	// Проверяем формат и дубликаты кастомных кодов внутри пакета
+	seen := make(map[string]bool, len(inputs))
+	var customCodes []string
+	for i := range inputs {
+		in, err := prepareLinkInput(inputs[i])
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		prepared[i] = in
+		if in.CustomCode == "" {
+			continue
+		}
+		if seen[in.CustomCode] {
+			results[i].Err = ErrShortCodeExists
+			continue
+		}
+		seen[in.CustomCode] = true
+		customCodes = append(customCodes, in.CustomCode)
+	}
+
+	taken, err := uc.linkRepo.FindExistingShortCodes(ctx, customCodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check short code existence: %w", err)
+	}
+	takenSet := make(map[string]bool, len(taken))
+	for _, code := range taken {
+		takenSet[code] = true
+	}
+
+	var generated []int
+	for i, in := range prepared {
+		if results[i].Err != nil {
+			continue
+		}
+		if in.CustomCode != "" {
+			if takenSet[in.CustomCode] {
+				results[i].Err = ErrShortCodeExists
+				continue
+			}
+			results[i].Link = newLink(in, in.CustomCode, userID)
+			continue
+		}
+		results[i].Link = newLink(in, "", userID)
+		generated = append(generated, i)
+	}
+
+	if err := uc.assignShortCodes(ctx, results, generated, seen); err != nil {
+		return nil, err
+	}
+
+	if atomic {
+		links := make([]*entity.Link, 0, len(results))
+		for _, r := range results {
+			if r.Err != nil {
+				// Ничего не создаем: ссылки без ошибок возвращаются без ID
+				for j := range results {
+					if results[j].Err == nil {
+						results[j].Link = nil
+					}
+				}
+				return results, nil
+			}
+			links = append(links, r.Link)
+		}
+
+		if err := uc.linkRepo.CreateBatch(ctx, links); err != nil {
+			return nil, fmt.Errorf("failed to create links: %w", err)
+		}
+		return results, nil
+	}
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		if err := uc.linkRepo.Create(ctx, results[i].Link); err != nil {
+			results[i].Link = nil
+			results[i].Err = fmt.Errorf("failed to create link: %w", err)
+		}
+	}
+
+	return results, nil
+}
+
+// assignShortCodes генерирует коды для ссылок без кастомного кода, проверяя
+// занятость кандидатов пачкой и избегая совпадений внутри пакета
+func (uc *linkUseCase) assignShortCodes(ctx context.Context, results []BatchLinkResult, pending []int, used map[string]bool) error {
+	for len(pending) > 0 {
+		candidates := make([]string, len(pending))
+		for k, i := range pending {
+			code := utils.GenerateShortCode(uc.shortURLLen)
+			for used[code] {
+				code = utils.GenerateShortCode(uc.shortURLLen)
+			}
+			used[code] = true
+			candidates[k] = code
+			results[i].Link.ShortCode = code
+		}
+
+		taken, err := uc.linkRepo.FindExistingShortCodes(ctx, candidates)
+		if err != nil {
+			return fmt.Errorf("failed to check short code existence: %w", err)
+		}
+		takenSet := make(map[string]bool, len(taken))
+		for _, code := range taken {
+			takenSet[code] = true
+		}
+
+		var retry []int
+		for _, i := range pending {
+			if takenSet[results[i].Link.ShortCode] {
+				retry = append(retry, i)
+			}
+		}
+		pending = retry
+	}
+
+	return nil
+}
+
+// prepareLinkInput проверяет данные новой ссылки без обращения к базе и дописывает
+// параметры UTM к адресу. В UTM результата - итоговые параметры адреса, включая указанные в нем вручную
+func prepareLinkInput(in LinkInput) (LinkInput, error) {
+	if !validator.IsValidURL(in.OriginalURL) {
+		return in, ErrInvalidURL
+	}
+
+	if in.ExpiresAt != nil && in.ExpiresAt.UTC().Before(time.Now().UTC()) {
+		return in, ErrExpirationInPast
+	}
+
+	if err := validateWindow(in.StartsAt, in.ExpiresAt); err != nil {
+		return in, err
+	}
+
+	if in.FallbackURL != "" && !isValidFallbackURL(in.FallbackURL) {
+		return in, ErrInvalidFallbackURL
+	}
+
+	if in.CustomCode != "" && !validator.IsValidShortCode(in.CustomCode) {
+		return in, ErrInvalidShortCode
+	}
+
+	in.Title = strings.TrimSpace(in.Title)
+	if utf8.RuneCountInString(in.Title) > maxTitleLength {
+		return in, ErrInvalidTitle
+	}
+
+	in.OriginalURL = applyUTM(in.OriginalURL, in.UTM)
+	in.UTM = utmFromURL(in.OriginalURL)
+	if err := validateUTM(in.UTM); err != nil {
+		return in, err
+	}
+
+	if (in.MaxClicks != nil && *in.MaxClicks < 1) || (in.DeleteWhenExhausted && in.MaxClicks == nil) {
+		return in, ErrInvalidMaxClicks
+	}
+
+	if len(in.Tags) > 0 {
+		tags, err := normalizeTagNames(in.Tags)
+		if err != nil {
+			return in, err
+		}
+		in.Tags = tags
+	}
+
+	if in.Password != "" {
+		hash, err := hashLinkPassword(in.Password)
+		if err != nil {
+			return in, err
+		}
+		in.passwordHash = hash
+	}
+
+	return in, nil
+}
+
+// validateWindow проверяет, что ссылка открывается раньше, чем истекает
+func validateWindow(startsAt, expiresAt *time.Time) error {
+	if startsAt != nil && expiresAt != nil && !startsAt.Before(*expiresAt) {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// isValidFallbackURL проверяет резервный адрес. В отличие от адреса ссылки схема обязательна:
+// без нее браузер воспринял бы редирект как относительный путь на нашем домене
+func isValidFallbackURL(rawURL string) bool {
+	return (strings.HasPrefix(rawURL, "http://") || strings.HasPrefix(rawURL, "https://")) && validator.IsValidURL(rawURL)
+}
+
+// hashLinkPassword проверяет длину пароля ссылки и возвращает его bcrypt-хеш
+func hashLinkPassword(password string) (string, error) {
+	if len(password) < minLinkPasswordLength || len(password) > maxLinkPasswordLength {
+		return "", ErrInvalidLinkPassword
+	}
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash link password: %w", err)
+	}
+	return hash, nil
+}
+
+// newLink собирает новую активную ссылку из подготовленных данных
+func newLink(in LinkInput, shortCode string, userID *int64) *entity.Link {
+	now := time.Now()
+	return &entity.Link{
+		ShortCode:           shortCode,
+		OriginalURL:         in.OriginalURL,
+		Title:               in.Title,
+		UserID:              userID,
+		IsActive:            true,
+		ExpiresAt:           in.ExpiresAt,
+		StartsAt:            in.StartsAt,
+		FallbackURL:         in.FallbackURL,
+		UTM:                 in.UTM,
+		PasswordHash:        in.passwordHash,
+		MaxClicks:           in.MaxClicks,
+		DeleteWhenExhausted: in.DeleteWhenExhausted,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+}
+
+// GetLinkByShortCode получает ссылку по короткому коду с проверкой активности, времени открытия и срока действия
 func (uc *linkUseCase) GetLinkByShortCode(ctx context.Context, shortCode string) (*entity.Link, error) {
 	link, err := uc.linkRepo.GetByShortCode(ctx, shortCode)
 	if err != nil {
@@ -121,25 +457,92 @@ func (uc *linkUseCase) GetLinkByShortCode(ctx context.Context, shortCode string)
 		return nil, ErrLinkNotFound
 	}
 
-	if link.ExpiresAt != nil && link.ExpiresAt.Before(time.Now().UTC()) {
+	if link.DisabledByAdmin {
+		return nil, ErrLinkDisabled
+	}
+
+	if !link.IsActive {
+		return nil, ErrLinkInactive
+	}
+
+	now := time.Now().UTC()
+
+	if link.StartsAt != nil && now.Before(*link.StartsAt) {
+		return nil, ErrLinkNotStarted
+	}
+
+	if link.ExpiresAt != nil && link.ExpiresAt.Before(now) {
 		return nil, ErrLinkExpired
 	}
 
+	// Копия из кэша может отставать, окончательно лимит проверяет RecordClick
+	if link.IsExhausted() {
+		return nil, ErrLinkExhausted
+	}
+
 	return link, nil
 }
 
-// GetUserLinks получает список ссылок пользователя с пагинацией
-func (uc *linkUseCase) GetUserLinks(ctx context.Context, userID int64, offset, limit int) ([]*entity.Link, error) {
-	links, err := uc.linkRepo.GetByUserID(ctx, userID, offset, limit)
+// GetUserLinks получает страницу ссылок пользователя, подходящих под фильтр, и их общее количество.
+// Владелец берется из userID, даже если в фильтре указан другой
+func (uc *linkUseCase) GetUserLinks(ctx context.Context, userID int64, filter entity.LinkFilter) ([]*entity.Link, int64, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.LinkSortCreated
+	}
+	if !filter.Sort.IsValid() {
+		return nil, 0, ErrInvalidSort
+	}
+	filter.UserID = &userID
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	links, err := uc.linkRepo.List(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user links: %w", err)
+		return nil, 0, fmt.Errorf("failed to get user links: %w", err)
+	}
+
+	total, err := uc.linkRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count user links: %w", err)
+	}
+
+	return links, total, nil
+}
+
+// exportPageSize - размер страницы, которой ссылки читаются при экспорте
+const exportPageSize = 500
+
+// ExportLinks последовательно передает в visit все ссылки пользователя, читая их страницами,
+// чтобы экспорт не держал в памяти весь список
+func (uc *linkUseCase) ExportLinks(ctx context.Context, userID int64, visit func(*entity.Link) error) error {
+	filter := entity.LinkFilter{UserID: &userID, Limit: exportPageSize}
+
+	for {
+		links, err := uc.linkRepo.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list links: %w", err)
+		}
+
+		for _, link := range links {
+			if err := visit(link); err != nil {
+				return err
+			}
+		}
+
+		if len(links) < exportPageSize {
+			return nil
+		}
+		filter.Offset += exportPageSize
 	}
-	return links, nil
 }
 
 // GetLink возвращает ссылку по ID с проверкой прав
 func (uc *linkUseCase) GetLink(ctx context.Context, linkID int64, userID int64) (*entity.Link, error) {
-	link, err := uc.linkRepo.GetByID(ctx, linkID)
+	return getOwnedLink(ctx, uc.linkRepo, linkID, userID)
+}
+
+// getOwnedLink возвращает ссылку, если она принадлежит пользователю
+func getOwnedLink(ctx context.Context, linkRepo repository.LinkRepository, linkID int64, userID int64) (*entity.Link, error) {
+	link, err := linkRepo.GetByID(ctx, linkID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get link: %w", err)
 	}
@@ -152,35 +555,111 @@ func (uc *linkUseCase) GetLink(ctx context.Context, linkID int64, userID int64)
 	return link, nil
 }
 
-// UpdateLink обновляет информацию о ссылке
-func (uc *linkUseCase) UpdateLink(ctx context.Context, linkID int64, userID int64, expiresAt *time.Time) error {
-	link, err := uc.linkRepo.GetByID(ctx, linkID)
+// UpdateLink применяет частичное обновление ссылки и возвращает ее новую версию
+func (uc *linkUseCase) UpdateLink(ctx context.Context, linkID int64, userID int64, update LinkUpdate) (*entity.Link, error) {
+	link, err := uc.GetLink(ctx, linkID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get link: %w", err)
+		return nil, err
 	}
-	if link == nil {
-		return ErrLinkNotFound
+
+	if update.OriginalURL != nil {
+		if !validator.IsValidURL(*update.OriginalURL) {
+			return nil, ErrInvalidURL
+		}
+		utm := utmFromURL(*update.OriginalURL)
+		if err := validateUTM(utm); err != nil {
+			return nil, err
+		}
+		link.OriginalURL = *update.OriginalURL
+		link.UTM = utm
 	}
 
-	if link.UserID == nil || *link.UserID != userID {
-		return ErrUnauthorized
+	if update.Title != nil {
+		title := strings.TrimSpace(*update.Title)
+		if utf8.RuneCountInString(title) > maxTitleLength {
+			return nil, ErrInvalidTitle
+		}
+		link.Title = title
+	}
+
+	if update.ShortCode != nil && *update.ShortCode != link.ShortCode {
+		if !validator.IsValidShortCode(*update.ShortCode) {
+			return nil, ErrInvalidShortCode
+		}
+		exists, err := uc.linkRepo.ExistsByShortCode(ctx, *update.ShortCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check short code existence: %w", err)
+		}
+		if exists {
+			return nil, ErrShortCodeExists
+		}
+		link.ShortCode = *update.ShortCode
+	}
+
+	switch {
+	case update.ClearExpiresAt:
+		link.ExpiresAt = nil
+	case update.ExpiresAt != nil:
+		if update.ExpiresAt.UTC().Before(time.Now().UTC()) {
+			return nil, ErrExpirationInPast
+		}
+		link.ExpiresAt = update.ExpiresAt
+	}
+
+	switch {
+	case update.ClearStartsAt:
+		link.StartsAt = nil
+	case update.StartsAt != nil:
+		link.StartsAt = update.StartsAt
+	}
+
+	if err := validateWindow(link.StartsAt, link.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	if update.IsActive != nil {
+		// Владелец может приостановить отключенную администратором ссылку, но не включить ее
+		if *update.IsActive && link.DisabledByAdmin {
+			return nil, ErrLinkDisabled
+		}
+		link.IsActive = *update.IsActive
 	}
 
-	if expiresAt != nil && expiresAt.UTC().Before(time.Now().UTC()) {
-		return ErrExpirationInPast
+	if update.FallbackURL != nil {
+		if *update.FallbackURL != "" && !isValidFallbackURL(*update.FallbackURL) {
+			return nil, ErrInvalidFallbackURL
+		}
+		link.FallbackURL = *update.FallbackURL
+	}
+
+	if update.Password != nil {
+		link.PasswordHash = ""
+		if *update.Password != "" {
+			hash, err := hashLinkPassword(*update.Password)
+			if err != nil {
+				return nil, err
+			}
+			link.PasswordHash = hash
+		}
 	}
 
-	link.ExpiresAt = expiresAt
 	link.UpdatedAt = time.Now().UTC()
 
 	if err := uc.linkRepo.Update(ctx, link); err != nil {
-		return fmt.Errorf("failed to update link: %w", err)
+		return nil, fmt.Errorf("failed to update link: %w", err)
 	}
 
-	return nil
+	return link, nil
 }
 
-// DeleteLink удаляет ссылку с проверкой прав доступа
+// SetLinkActive включает или приостанавливает ссылку, не трогая остальные поля
+func (uc *linkUseCase) SetLinkActive(ctx context.Context, linkID int64, userID int64, active bool) error {
+	_, err := uc.UpdateLink(ctx, linkID, userID, LinkUpdate{IsActive: &active})
+	return err
+}
+
+// DeleteLink переносит ссылку в корзину с проверкой прав доступа.
+// Ссылка сразу перестает открываться, а ее клики сохраняются до очистки корзины
 func (uc *linkUseCase) DeleteLink(ctx context.Context, linkID int64, userID int64) error {
 	link, err := uc.linkRepo.GetByID(ctx, linkID)
 	if err != nil {
@@ -201,8 +680,61 @@ func (uc *linkUseCase) DeleteLink(ctx context.Context, linkID int64, userID int6
 	return nil
 }
 
-// RecordClick записывает клик по ссылке и увеличивает счетчик
-func (uc *linkUseCase) RecordClick(ctx context.Context, shortCode, ipAddress, userAgent, referer string) (*entity.Link, error) {
+// GetTrash получает страницу удаленных ссылок пользователя, начиная с удаленных последними, и их общее количество
+func (uc *linkUseCase) GetTrash(ctx context.Context, userID int64, filter entity.LinkFilter) ([]*entity.Link, int64, error) {
+	filter.UserID = &userID
+	filter.Deleted = true
+	filter.Sort = entity.LinkSortDeleted
+	filter.Ascending = false
+
+	links, err := uc.linkRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted links: %w", err)
+	}
+
+	total, err := uc.linkRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted links: %w", err)
+	}
+
+	return links, total, nil
+}
+
+// RestoreLink возвращает ссылку из корзины с проверкой прав доступа
+func (uc *linkUseCase) RestoreLink(ctx context.Context, linkID int64, userID int64) (*entity.Link, error) {
+	link, err := uc.linkRepo.GetDeletedByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+	if link.UserID == nil || *link.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	if err := uc.linkRepo.Restore(ctx, linkID); err != nil {
+		return nil, fmt.Errorf("failed to restore link: %w", err)
+	}
+
+	return uc.GetLink(ctx, linkID, userID)
+}
+
+// GetFallbackURL возвращает адрес, куда вести посетителя, если ссылку нельзя открыть:
+// резервный адрес ссылки или ее владельца. Пустая строка - резервного адреса нет
+func (uc *linkUseCase) GetFallbackURL(ctx context.Context, shortCode string) (string, error) {
+	fallbackURL, err := uc.linkRepo.GetFallbackURL(ctx, shortCode)
+	if err != nil {
+		return "", fmt.Errorf("failed to get fallback URL: %w", err)
+	}
+	return fallbackURL, nil
+}
+
+// RecordClick записывает клик по ссылке и увеличивает счетчик.
+// При наличии очереди клик только ставится в нее, запись в базу идет в фоне.
+// Для ссылки с паролем клик записывается только после проверки password.
+// Порядок проверок: доступность ссылки (GetLinkByShortCode), пароль, затем списание лимита
+func (uc *linkUseCase) RecordClick(ctx context.Context, shortCode, password, ipAddress, userAgent, referer string) (*entity.Link, error) {
 	link, err := uc.GetLinkByShortCode(ctx, shortCode)
 	if err != nil {
 		return nil, err
@@ -216,14 +748,38 @@ func (uc *linkUseCase) RecordClick(ctx context.Context, shortCode, ipAddress, us
 		ClickedAt: time.Now(),
 	}
 
-	if link.ExpiresAt != nil && link.ExpiresAt.UTC().Before(time.Now().UTC()) {
-		return nil, ErrExpiration
+	if link.IsPasswordProtected() {
+		if password == "" {
+			return nil, ErrPasswordRequired
+		}
+		if !utils.CheckPasswordHash(password, link.PasswordHash) {
+			return nil, ErrWrongPassword
+		}
+	}
+
+	for _, enricher := range uc.enrichers {
+		enricher.Enrich(ctx, click)
+	}
+
+	if err := uc.consumeUse(ctx, link); err != nil {
+		return nil, err
+	}
+
+	if uc.clickQueue != nil {
+		// Потерянный клик не должен ломать редирект: очередь сама логирует отказы
+		_ = uc.clickQueue.Enqueue(ctx, click)
+		return link, nil
 	}
 
 	if err := uc.linkClickRepo.Create(ctx, click); err != nil {
 		return nil, fmt.Errorf("failed to record click: %w", err)
 	}
 
+	// Клик бота сохраняется с флагом, но не попадает в счетчик ссылки
+	if click.IsBot {
+		return link, nil
+	}
+
 	if err := uc.linkRepo.IncrementClicks(ctx, link.ID); err != nil {
 		return nil, fmt.Errorf("failed to increment clicks: %w", err)
 	}
@@ -231,8 +787,37 @@ func (uc *linkUseCase) RecordClick(ctx context.Context, shortCode, ipAddress, us
 	return link, nil
 }
 
+// consumeUse списывает переход в счет лимита ссылки. Переходы ботов тоже расходуют лимит:
+// User-Agent задает клиент, и исключение для ботов позволило бы открывать ссылку без ограничений.
+// Исчерпанная ссылка с DeleteWhenExhausted переносится в корзину
+func (uc *linkUseCase) consumeUse(ctx context.Context, link *entity.Link) error {
+	if link.MaxClicks == nil {
+		return nil
+	}
+
+	uses, ok, err := uc.linkRepo.ConsumeUse(ctx, link.ID)
+	if err != nil {
+		return fmt.Errorf("failed to consume link use: %w", err)
+	}
+	if !ok {
+		return ErrLinkExhausted
+	}
+	link.Uses = uses
+
+	if link.DeleteWhenExhausted && link.IsExhausted() {
+		// Переход уже засчитан, поэтому ошибка удаления не должна ломать редирект:
+		// неудаленная ссылка исчерпана и больше не откроется
+		_ = uc.linkRepo.Delete(ctx, link.ID)
+	}
+	return nil
+}
+
 // GetLinkStats получает статистику по ссылке за указанный период
-func (uc *linkUseCase) GetLinkStats(ctx context.Context, linkID int64, userID int64, from, to time.Time) (*entity.LinkStats, error) {
+func (uc *linkUseCase) GetLinkStats(ctx context.Context, linkID int64, userID int64, query StatsQuery) (*entity.LinkStats, error) {
+	if err := normalizeStatsQuery(&query); err != nil {
+		return nil, err
+	}
+
 	link, err := uc.linkRepo.GetByID(ctx, linkID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get link: %w", err)
@@ -245,10 +830,170 @@ func (uc *linkUseCase) GetLinkStats(ctx context.Context, linkID int64, userID in
 		return nil, ErrUnauthorized
 	}
 
-	stats, err := uc.linkClickRepo.GetStats(ctx, linkID, from, to)
+	stats, err := uc.linkClickRepo.GetStats(ctx, entity.StatsFilter{
+		LinkID:      linkID,
+		From:        query.From,
+		To:          query.To,
+		IncludeBots: query.IncludeBots,
+		Location:    query.Location,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stats: %w", err)
 	}
 
+	stats.Granularity = query.Granularity
+	stats.Timezone = query.Location.String()
+	stats.Timeline = fillTimeline(stats.Timeline, query.From, query.To, query.Granularity, query.Location)
+
 	return stats, nil
 }
+
+// ListClicks возвращает страницу журнала кликов ссылки, от новых к старым
+func (uc *linkUseCase) ListClicks(ctx context.Context, linkID int64, userID int64, query ClickQuery) (*ClickPage, error) {
+	if _, err := uc.GetLink(ctx, linkID, userID); err != nil {
+		return nil, err
+	}
+
+	filter := entity.ClickFilter{
+		LinkID: linkID,
+		From:   toUTC(query.From),
+		To:     toUTC(query.To),
+		Limit:  query.Limit + 1, // лишняя запись показывает, есть ли следующая страница
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeClickCursor(query.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = cursor
+	}
+
+	clicks, err := uc.linkClickRepo.GetByLinkID(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clicks: %w", err)
+	}
+
+	page := &ClickPage{Clicks: clicks}
+	if len(clicks) > query.Limit {
+		page.Clicks = clicks[:query.Limit]
+		last := page.Clicks[len(page.Clicks)-1]
+		page.NextCursor = encodeClickCursor(entity.ClickCursor{ClickedAt: last.ClickedAt, ID: last.ID})
+	}
+
+	// Наружу IP отдается только в виде хеша
+	for _, click := range page.Clicks {
+		click.IPAddress = utils.HashIP(click.IPAddress, uc.ipHashSalt)
+	}
+
+	return page, nil
+}
+
+// encodeClickCursor упаковывает позицию в журнале кликов в непрозрачную строку
+func encodeClickCursor(cursor entity.ClickCursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.ClickedAt.UnixMicro(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeClickCursor разбирает курсор, созданный encodeClickCursor
+func decodeClickCursor(value string) (*entity.ClickCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var micros, id int64
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &micros, &id); err != nil {
+		return nil, err
+	}
+
+	return &entity.ClickCursor{ClickedAt: time.UnixMicro(micros).UTC(), ID: id}, nil
+}
+
+// normalizeStatsQuery подставляет значения по умолчанию и проверяет параметры временного ряда
+func normalizeStatsQuery(query *StatsQuery) error {
+	if query.Granularity == "" {
+		query.Granularity = entity.GranularityDay
+	}
+	if !query.Granularity.IsValid() {
+		return ErrInvalidGranularity
+	}
+	if query.Location == nil {
+		query.Location = time.UTC
+	}
+	if countBuckets(query.From, query.To, query.Granularity) > maxTimelineBuckets {
+		return ErrTimelineTooLong
+	}
+	return nil
+}
+
+// bucketStart возвращает начало интервала, в который попадает t, по местному времени loc
+func bucketStart(t time.Time, g entity.Granularity, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch g {
+	case entity.GranularityHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case entity.GranularityWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case entity.GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// nextBucket возвращает начало следующего интервала.
+// Дни, недели и месяцы считаются по календарю, поэтому переход на летнее время их не сдвигает
+func nextBucket(start time.Time, g entity.Granularity, loc *time.Location) time.Time {
+	switch g {
+	case entity.GranularityHour:
+		// Без повторного округления: при переводе часов назад один и тот же час по местному времени повторяется
+		return start.Add(time.Hour)
+	case entity.GranularityWeek:
+		return bucketStart(start.AddDate(0, 0, 7), g, loc)
+	case entity.GranularityMonth:
+		return bucketStart(start.AddDate(0, 1, 0), g, loc)
+	default:
+		return bucketStart(start.AddDate(0, 0, 1), g, loc)
+	}
+}
+
+// countBuckets оценивает число интервалов в периоде без их перебора
+func countBuckets(from, to time.Time, g entity.Granularity) int64 {
+	if !to.After(from) {
+		return 1
+	}
+	span := to.Sub(from)
+	switch g {
+	case entity.GranularityHour:
+		return int64(span/time.Hour) + 2
+	case entity.GranularityWeek:
+		return int64(span/(7*24*time.Hour)) + 2
+	case entity.GranularityMonth:
+		return int64(span/(28*24*time.Hour)) + 2
+	default:
+		return int64(span/(24*time.Hour)) + 2
+	}
+}
+
+// fillTimeline укрупняет временной ряд до гранулярности g и дополняет его нулевыми интервалами от from до to включительно
+func fillTimeline(buckets []entity.TimeBucket, from, to time.Time, g entity.Granularity, loc *time.Location) []entity.TimeBucket {
+	counts := make(map[int64]int64, len(buckets))
+	for _, b := range buckets {
+		counts[bucketStart(b.Start, g, loc).Unix()] += b.Clicks
+	}
+
+	timeline := make([]entity.TimeBucket, 0, countBuckets(from, to, g))
+	for start := bucketStart(from, g, loc); !start.After(to); {
+		timeline = append(timeline, entity.TimeBucket{Start: start, Clicks: counts[start.Unix()]})
+
+		next := nextBucket(start, g, loc)
+		if !next.After(start) {
+			break
+		}
+		start = next
+	}
+
+	return timeline
+}
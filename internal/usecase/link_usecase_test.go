@@ -2,10 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -36,6 +39,14 @@ func (m *MockLinkRepository) GetByID(ctx context.Context, id int64) (*entity.Lin
 	return args.Get(0).(*entity.Link), args.Error(1)
 }
 
+func (m *MockLinkRepository) GetByIDs(ctx context.Context, ids []int64) ([]*entity.Link, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Link), args.Error(1)
+}
+
 func (m *MockLinkRepository) GetByUserID(ctx context.Context, userID int64, offset, limit int) ([]*entity.Link, error) {
 	args := m.Called(ctx, userID, offset, limit)
 	if args.Get(0) == nil {
@@ -54,19 +65,63 @@ func (m *MockLinkRepository) Delete(ctx context.Context, id int64) error {
 	return args.Error(0)
 }
 
+func (m *MockLinkRepository) SetDisabledByAdmin(ctx context.Context, id int64, disabled bool) error {
+	args := m.Called(ctx, id, disabled)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) GetDeletedByID(ctx context.Context, id int64) (*entity.Link, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Link), args.Error(1)
+}
+
+func (m *MockLinkRepository) Restore(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockLinkRepository) IncrementClicks(ctx context.Context, linkID int64) error {
 	args := m.Called(ctx, linkID)
 	return args.Error(0)
 }
 
-func (m *MockLinkRepository) GetExpiredLinks(ctx context.Context, before time.Time) ([]*entity.Link, error) {
-	args := m.Called(ctx, before)
+func (m *MockLinkRepository) GetFallbackURL(ctx context.Context, shortCode string) (string, error) {
+	args := m.Called(ctx, shortCode)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLinkRepository) ConsumeUse(ctx context.Context, linkID int64) (int64, bool, error) {
+	args := m.Called(ctx, linkID)
+	return args.Get(0).(int64), args.Bool(1), args.Error(2)
+}
+
+func (m *MockLinkRepository) GetExpiredLinks(ctx context.Context, before time.Time, limit int) ([]*entity.Link, error) {
+	args := m.Called(ctx, before, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.Link), args.Error(1)
 }
 
+func (m *MockLinkRepository) PurgeExpired(ctx context.Context, ids []int64, before time.Time, archive bool) ([]string, error) {
+	args := m.Called(ctx, ids, before, archive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockLinkRepository) CountByUserID(ctx context.Context, userID int64) (int64, error) {
 	args := m.Called(ctx, userID)
 	return args.Get(0).(int64), args.Error(1)
@@ -77,6 +132,32 @@ func (m *MockLinkRepository) ExistsByShortCode(ctx context.Context, shortCode st
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockLinkRepository) CreateBatch(ctx context.Context, links []*entity.Link) error {
+	args := m.Called(ctx, links)
+	return args.Error(0)
+}
+
+func (m *MockLinkRepository) FindExistingShortCodes(ctx context.Context, shortCodes []string) ([]string, error) {
+	args := m.Called(ctx, shortCodes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockLinkRepository) List(ctx context.Context, filter entity.LinkFilter) ([]*entity.Link, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Link), args.Error(1)
+}
+
+func (m *MockLinkRepository) Count(ctx context.Context, filter entity.LinkFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockLinkClickRepository is a mock implementation of LinkClickRepository
 type MockLinkClickRepository struct {
 	mock.Mock
@@ -87,22 +168,35 @@ func (m *MockLinkClickRepository) Create(ctx context.Context, click *entity.Link
 	return args.Error(0)
 }
 
-func (m *MockLinkClickRepository) GetByLinkID(ctx context.Context, linkID int64, offset, limit int) ([]*entity.LinkClick, error) {
-	args := m.Called(ctx, linkID, offset, limit)
+func (m *MockLinkClickRepository) CreateBatch(ctx context.Context, clicks []*entity.LinkClick, counts map[int64]int64) error {
+	args := m.Called(ctx, clicks, counts)
+	return args.Error(0)
+}
+
+func (m *MockLinkClickRepository) GetByLinkID(ctx context.Context, filter entity.ClickFilter) ([]*entity.LinkClick, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.LinkClick), args.Error(1)
 }
 
-func (m *MockLinkClickRepository) GetStats(ctx context.Context, linkID int64, from, to time.Time) (*entity.LinkStats, error) {
-	args := m.Called(ctx, linkID, from, to)
+func (m *MockLinkClickRepository) GetStats(ctx context.Context, filter entity.StatsFilter) (*entity.LinkStats, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.LinkStats), args.Error(1)
 }
 
+func (m *MockLinkClickRepository) GetTotals(ctx context.Context, filter entity.StatsFilter) (*entity.ClickTotals, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ClickTotals), args.Error(1)
+}
+
 func (m *MockLinkClickRepository) CountByLinkID(ctx context.Context, linkID int64) (int64, error) {
 	args := m.Called(ctx, linkID)
 	return args.Get(0).(int64), args.Error(1)
@@ -120,7 +214,7 @@ func TestLinkUseCase_CreateLink(t *testing.T) {
 	mockLinkRepo := new(MockLinkRepository)
 	mockClickRepo := new(MockLinkClickRepository)
 
-	uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, 6, "http://localhost:8080")
+	uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
 
 	t.Run("Success - Create link with auto-generated code", func(t *testing.T) {
 		// Mock expectations
@@ -128,7 +222,7 @@ func TestLinkUseCase_CreateLink(t *testing.T) {
 		mockLinkRepo.On("Create", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
 
 		// Execute
-		link, err := uc.CreateLink(ctx, "https://example.com", nil, "", nil)
+		link, err := uc.CreateLink(ctx, nil, LinkInput{OriginalURL: "https://example.com"})
 
 		// Assertions
 		assert.NoError(t, err)
@@ -148,7 +242,7 @@ func TestLinkUseCase_CreateLink(t *testing.T) {
 		mockLinkRepo.On("Create", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
 
 		// Execute
-		link, err := uc.CreateLink(ctx, "https://example.com", nil, customCode, nil)
+		link, err := uc.CreateLink(ctx, nil, LinkInput{OriginalURL: "https://example.com", CustomCode: customCode})
 
 		// Assertions
 		assert.NoError(t, err)
@@ -157,6 +251,128 @@ func TestLinkUseCase_CreateLink(t *testing.T) {
 
 		mockLinkRepo.AssertExpectations(t)
 	})
+
+	t.Run("Success - UTM parameters are merged into the URL", func(t *testing.T) {
+		mockLinkRepo.On("ExistsByShortCode", ctx, mock.AnythingOfType("string")).Return(false, nil)
+		mockLinkRepo.On("Create", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
+
+		link, err := uc.CreateLink(ctx, nil, LinkInput{
+			OriginalURL: "https://example.com/p?id=7&utm_source=old#top",
+			UTM:         entity.UTM{Source: "newsletter", Campaign: "spring sale"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/p?id=7&utm_source=newsletter&utm_campaign=spring+sale#top", link.OriginalURL)
+		assert.Equal(t, entity.UTM{Source: "newsletter", Campaign: "spring sale"}, link.UTM)
+	})
+
+	t.Run("Error - UTM parameter is too long", func(t *testing.T) {
+		link, err := uc.CreateLink(ctx, nil, LinkInput{
+			OriginalURL: "https://example.com",
+			UTM:         entity.UTM{Campaign: strings.Repeat("a", 256)},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidUTM)
+		assert.Nil(t, link)
+	})
+
+	t.Run("Success - Password is stored as a hash", func(t *testing.T) {
+		mockLinkRepo.On("ExistsByShortCode", ctx, mock.AnythingOfType("string")).Return(false, nil)
+		mockLinkRepo.On("Create", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
+
+		link, err := uc.CreateLink(ctx, nil, LinkInput{OriginalURL: "https://example.com", Password: "s3cret"})
+
+		assert.NoError(t, err)
+		assert.True(t, link.IsPasswordProtected())
+		assert.NotEqual(t, "s3cret", link.PasswordHash)
+		assert.True(t, utils.CheckPasswordHash("s3cret", link.PasswordHash))
+	})
+
+	t.Run("Error - Password is too short", func(t *testing.T) {
+		link, err := uc.CreateLink(ctx, nil, LinkInput{OriginalURL: "https://example.com", Password: "abc"})
+
+		assert.ErrorIs(t, err, ErrInvalidLinkPassword)
+		assert.Nil(t, link)
+	})
+
+	t.Run("Error - Link starts after it expires", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		startsAt := expiresAt.Add(time.Hour)
+
+		link, err := uc.CreateLink(ctx, nil, LinkInput{OriginalURL: "https://example.com", StartsAt: &startsAt, ExpiresAt: &expiresAt})
+
+		assert.ErrorIs(t, err, ErrInvalidWindow)
+		assert.Nil(t, link)
+	})
+
+	t.Run("Error - Relative fallback URL", func(t *testing.T) {
+		link, err := uc.CreateLink(ctx, nil, LinkInput{OriginalURL: "https://example.com", FallbackURL: "/sale-ended"})
+
+		assert.ErrorIs(t, err, ErrInvalidFallbackURL)
+		assert.Nil(t, link)
+	})
+
+	t.Run("Error - Auto-delete without click limit", func(t *testing.T) {
+		link, err := uc.CreateLink(ctx, nil, LinkInput{OriginalURL: "https://example.com", DeleteWhenExhausted: true})
+
+		assert.ErrorIs(t, err, ErrInvalidMaxClicks)
+		assert.Nil(t, link)
+	})
+}
+
+func TestApplyUTM(t *testing.T) {
+	utm := entity.UTM{Source: "news", Medium: "email"}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"no query", "https://example.com/a", "https://example.com/a?utm_source=news&utm_medium=email"},
+		{"existing query is kept verbatim", "https://example.com/a?q=a%20b&x", "https://example.com/a?q=a%20b&x&utm_source=news&utm_medium=email"},
+		{"same parameter is replaced", "https://example.com/?utm_medium=cpc&utm_campaign=x", "https://example.com/?utm_campaign=x&utm_source=news&utm_medium=email"},
+		{"fragment stays last", "https://example.com/#section", "https://example.com/?utm_source=news&utm_medium=email#section"},
+		{"empty query", "https://example.com/?", "https://example.com/?utm_source=news&utm_medium=email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, applyUTM(tt.url, utm))
+		})
+	}
+
+	assert.Equal(t, "https://example.com/?a=1", applyUTM("https://example.com/?a=1", entity.UTM{}))
+}
+
+func TestLinkUseCase_GetUserLinks(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("Success - Filter is scoped to the user and counted", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		otherID := int64(2)
+		expected := entity.LinkFilter{UserID: &userID, Search: "promo", Sort: entity.LinkSortCreated, Limit: 20}
+		links := []*entity.Link{{ID: 1, ShortCode: "promo1"}}
+		mockLinkRepo.On("List", ctx, expected).Return(links, nil)
+		mockLinkRepo.On("Count", ctx, expected).Return(int64(41), nil)
+
+		result, total, err := uc.GetUserLinks(ctx, userID, entity.LinkFilter{UserID: &otherID, Search: " promo ", Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, links, result)
+		assert.Equal(t, int64(41), total)
+		mockLinkRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Unknown sort field", func(t *testing.T) {
+		uc := NewLinkUseCase(new(MockLinkRepository), new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		_, _, err := uc.GetUserLinks(ctx, userID, entity.LinkFilter{Sort: "title"})
+
+		assert.ErrorIs(t, err, ErrInvalidSort)
+	})
 }
 
 func TestLinkUseCase_GetLinkByShortCode(t *testing.T) {
@@ -164,7 +380,7 @@ func TestLinkUseCase_GetLinkByShortCode(t *testing.T) {
 	mockLinkRepo := new(MockLinkRepository)
 	mockClickRepo := new(MockLinkClickRepository)
 
-	uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, 6, "http://localhost:8080")
+	uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
 
 	t.Run("Success", func(t *testing.T) {
 		now := time.Now()
@@ -173,6 +389,7 @@ func TestLinkUseCase_GetLinkByShortCode(t *testing.T) {
 			ShortCode:   "abc123",
 			OriginalURL: "https://example.com",
 			Clicks:      0,
+			IsActive:    true,
 			CreatedAt:   now,
 			UpdatedAt:   now,
 		}
@@ -206,12 +423,22 @@ func TestLinkUseCase_GetLinkByShortCode(t *testing.T) {
 		mockLinkRepo.AssertExpectations(t)
 	})
 
+	t.Run("Error - Link disabled by an administrator", func(t *testing.T) {
+		mockLinkRepo.On("GetByShortCode", ctx, "banned").Return(&entity.Link{ID: 2, ShortCode: "banned", IsActive: true, DisabledByAdmin: true}, nil)
+
+		link, err := uc.GetLinkByShortCode(ctx, "banned")
+
+		assert.ErrorIs(t, err, ErrLinkDisabled)
+		assert.Nil(t, link)
+	})
+
 	t.Run("Error - Link expired", func(t *testing.T) {
 		expiredTime := time.Now().Add(-1 * time.Hour)
 		expiredLink := &entity.Link{
 			ID:          1,
 			ShortCode:   "expired",
 			OriginalURL: "https://example.com",
+			IsActive:    true,
 			ExpiresAt:   &expiredTime,
 		}
 
@@ -228,4 +455,878 @@ func TestLinkUseCase_GetLinkByShortCode(t *testing.T) {
 
 		mockLinkRepo.AssertExpectations(t)
 	})
+
+	t.Run("Error - Link inactive", func(t *testing.T) {
+		inactiveLink := &entity.Link{
+			ID:          2,
+			ShortCode:   "paused",
+			OriginalURL: "https://example.com",
+			IsActive:    false,
+		}
+
+		// Mock expectations
+		mockLinkRepo.On("GetByShortCode", ctx, "paused").Return(inactiveLink, nil)
+
+		// Execute
+		link, err := uc.GetLinkByShortCode(ctx, "paused")
+
+		// Assertions
+		assert.Error(t, err)
+		assert.Equal(t, ErrLinkInactive, err)
+		assert.Nil(t, link)
+
+		mockLinkRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Link not started yet", func(t *testing.T) {
+		startsAt := time.Now().Add(time.Hour)
+		scheduledLink := &entity.Link{
+			ID:          3,
+			ShortCode:   "launch",
+			OriginalURL: "https://example.com",
+			IsActive:    true,
+			StartsAt:    &startsAt,
+		}
+
+		mockLinkRepo.On("GetByShortCode", ctx, "launch").Return(scheduledLink, nil)
+
+		link, err := uc.GetLinkByShortCode(ctx, "launch")
+
+		assert.ErrorIs(t, err, ErrLinkNotStarted)
+		assert.Nil(t, link)
+	})
+
+	t.Run("Success - Link after its start", func(t *testing.T) {
+		startsAt := time.Now().Add(-time.Minute)
+		liveLink := &entity.Link{
+			ID:          4,
+			ShortCode:   "live",
+			OriginalURL: "https://example.com",
+			IsActive:    true,
+			StartsAt:    &startsAt,
+		}
+
+		mockLinkRepo.On("GetByShortCode", ctx, "live").Return(liveLink, nil)
+
+		link, err := uc.GetLinkByShortCode(ctx, "live")
+
+		assert.NoError(t, err)
+		assert.Equal(t, liveLink, link)
+	})
+}
+
+// MockClickQueue is a mock implementation of ClickQueue
+type MockClickQueue struct {
+	mock.Mock
+}
+
+func (m *MockClickQueue) Enqueue(ctx context.Context, click *entity.LinkClick) error {
+	args := m.Called(ctx, click)
+	return args.Error(0)
+}
+
+func TestLinkUseCase_RecordClick(t *testing.T) {
+	ctx := context.Background()
+	link := &entity.Link{
+		ID:          1,
+		ShortCode:   "abc123",
+		OriginalURL: "https://example.com",
+		IsActive:    true,
+	}
+
+	t.Run("Success - Click is queued without touching the database", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+		mockQueue.On("Enqueue", ctx, mock.MatchedBy(func(click *entity.LinkClick) bool {
+			return click.LinkID == 1 && click.IPAddress == "10.0.0.1"
+		})).Return(nil)
+
+		result, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, link, result)
+		mockQueue.AssertExpectations(t)
+		mockClickRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockLinkRepo.AssertNotCalled(t, "IncrementClicks", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Full queue does not break the redirect", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+		mockQueue.On("Enqueue", ctx, mock.Anything).Return(errors.New("queue is full"))
+
+		result, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, link, result)
+	})
+
+	t.Run("Success - Click is enriched with geolocation", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		resolver := stubGeoResolver{"81.2.69.160": {Country: "GB", City: "London"}}
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt", NewGeoEnricher(resolver))
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+		mockQueue.On("Enqueue", ctx, mock.MatchedBy(func(click *entity.LinkClick) bool {
+			return click.Country == "GB" && click.City == "London"
+		})).Return(nil)
+
+		_, err := uc.RecordClick(ctx, "abc123", "", "81.2.69.160", "Mozilla/5.0", "")
+
+		assert.NoError(t, err)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("Success - User-Agent is classified", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt", NewUserAgentEnricher(nil))
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+		mockQueue.On("Enqueue", ctx, mock.MatchedBy(func(click *entity.LinkClick) bool {
+			return click.DeviceType == "Tablet" && click.OS == "iPadOS" && click.Browser == "Safari" && !click.IsBot
+		})).Return(nil)
+
+		ua := "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+		_, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", ua, "")
+
+		assert.NoError(t, err)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("Success - Bot click is stored but not counted", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt", NewUserAgentEnricher(nil))
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+		mockClickRepo.On("Create", ctx, mock.MatchedBy(func(click *entity.LinkClick) bool {
+			return click.IsBot
+		})).Return(nil)
+
+		result, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, link, result)
+		mockClickRepo.AssertExpectations(t)
+		mockLinkRepo.AssertNotCalled(t, "IncrementClicks", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Unknown address leaves location empty", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt", NewGeoEnricher(stubGeoResolver{}))
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+		mockQueue.On("Enqueue", ctx, mock.MatchedBy(func(click *entity.LinkClick) bool {
+			return click.Country == "" && click.City == ""
+		})).Return(nil)
+
+		_, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.NoError(t, err)
+		mockQueue.AssertExpectations(t)
+	})
+}
+
+func TestLinkUseCase_RecordClick_Password(t *testing.T) {
+	ctx := context.Background()
+	hash, err := utils.HashPassword("s3cret")
+	assert.NoError(t, err)
+	link := &entity.Link{
+		ID:           1,
+		ShortCode:    "abc123",
+		OriginalURL:  "https://example.com",
+		IsActive:     true,
+		PasswordHash: hash,
+	}
+
+	t.Run("Success - Correct password records the click", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+		mockQueue.On("Enqueue", ctx, mock.Anything).Return(nil)
+
+		result, err := uc.RecordClick(ctx, "abc123", "s3cret", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, link, result)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing password", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+
+		result, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.ErrorIs(t, err, ErrPasswordRequired)
+		assert.Nil(t, result)
+		mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Wrong password is not counted as a click", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(link, nil)
+
+		result, err := uc.RecordClick(ctx, "abc123", "guess", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.ErrorIs(t, err, ErrWrongPassword)
+		assert.Nil(t, result)
+		mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLinkUseCase_RecordClick_MaxClicks(t *testing.T) {
+	ctx := context.Background()
+	newLimitedLink := func(deleteWhenExhausted bool) *entity.Link {
+		maxClicks := int64(2)
+		return &entity.Link{
+			ID:                  1,
+			ShortCode:           "abc123",
+			OriginalURL:         "https://example.com",
+			IsActive:            true,
+			MaxClicks:           &maxClicks,
+			Uses:                1,
+			DeleteWhenExhausted: deleteWhenExhausted,
+		}
+	}
+
+	t.Run("Success - Use is consumed before the click is queued", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(newLimitedLink(false), nil)
+		mockLinkRepo.On("ConsumeUse", ctx, int64(1)).Return(int64(2), true, nil)
+		mockQueue.On("Enqueue", ctx, mock.Anything).Return(nil)
+
+		link, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.NoError(t, err)
+		assert.True(t, link.IsExhausted())
+		mockQueue.AssertExpectations(t)
+		mockLinkRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Last use moves the link to the trash and still records the click", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(newLimitedLink(true), nil)
+		mockLinkRepo.On("ConsumeUse", ctx, int64(1)).Return(int64(2), true, nil)
+		mockLinkRepo.On("Delete", ctx, int64(1)).Return(nil)
+		mockQueue.On("Enqueue", ctx, mock.Anything).Return(nil)
+
+		link, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com", link.OriginalURL)
+		mockLinkRepo.AssertExpectations(t)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("Error - Bot User-Agent does not bypass the limit", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt", NewUserAgentEnricher(nil))
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(newLimitedLink(false), nil)
+		mockLinkRepo.On("ConsumeUse", ctx, int64(1)).Return(int64(0), false, nil)
+
+		link, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "curl/8.4.0", "")
+
+		assert.ErrorIs(t, err, ErrLinkExhausted)
+		assert.Nil(t, link)
+		mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Concurrent redirect took the last use", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockQueue := new(MockClickQueue)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), mockQueue, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(newLimitedLink(false), nil)
+		mockLinkRepo.On("ConsumeUse", ctx, int64(1)).Return(int64(0), false, nil)
+
+		link, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.ErrorIs(t, err, ErrLinkExhausted)
+		assert.Nil(t, link)
+		mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Exhausted link is refused without touching the counter", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), new(MockClickQueue), 6, "http://localhost:8080", "salt")
+
+		exhausted := newLimitedLink(false)
+		exhausted.Uses = 2
+		mockLinkRepo.On("GetByShortCode", ctx, "abc123").Return(exhausted, nil)
+
+		_, err := uc.RecordClick(ctx, "abc123", "", "10.0.0.1", "Mozilla/5.0", "")
+
+		assert.ErrorIs(t, err, ErrLinkExhausted)
+		mockLinkRepo.AssertNotCalled(t, "ConsumeUse", mock.Anything, mock.Anything)
+	})
+}
+
+type stubGeoResolver map[string]*entity.GeoLocation
+
+func (r stubGeoResolver) Lookup(ip string) (*entity.GeoLocation, error) {
+	return r[ip], nil
+}
+
+func TestLinkUseCase_Trash(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(7)
+	deletedAt := time.Now().Add(-time.Hour)
+
+	newDeletedLink := func(ownerID int64) *entity.Link {
+		return &entity.Link{ID: 1, ShortCode: "abc123", OriginalURL: "https://example.com", UserID: &ownerID, DeletedAt: &deletedAt}
+	}
+
+	t.Run("Success - Trash lists deleted links of the user, newest first", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		other := int64(99)
+		expected := entity.LinkFilter{UserID: &userID, Deleted: true, Sort: entity.LinkSortDeleted, Limit: 20}
+		mockLinkRepo.On("List", ctx, expected).Return([]*entity.Link{newDeletedLink(userID)}, nil)
+		mockLinkRepo.On("Count", ctx, expected).Return(int64(1), nil)
+
+		links, total, err := uc.GetTrash(ctx, userID, entity.LinkFilter{UserID: &other, Sort: entity.LinkSortClicks, Ascending: true, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Len(t, links, 1)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("Success - Restore takes the link out of the trash", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		restored := newDeletedLink(userID)
+		restored.DeletedAt = nil
+		mockLinkRepo.On("GetDeletedByID", ctx, int64(1)).Return(newDeletedLink(userID), nil)
+		mockLinkRepo.On("Restore", ctx, int64(1)).Return(nil)
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(restored, nil)
+
+		link, err := uc.RestoreLink(ctx, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, link.DeletedAt)
+		mockLinkRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Link of another user is not restored", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetDeletedByID", ctx, int64(1)).Return(newDeletedLink(99), nil)
+
+		_, err := uc.RestoreLink(ctx, 1, userID)
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		mockLinkRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Link not in the trash", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetDeletedByID", ctx, int64(1)).Return(nil, nil)
+
+		_, err := uc.RestoreLink(ctx, 1, userID)
+
+		assert.ErrorIs(t, err, ErrLinkNotFound)
+	})
+}
+
+func TestLinkUseCase_UpdateLink(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(7)
+
+	newLink := func() *entity.Link {
+		expiresAt := time.Now().Add(24 * time.Hour)
+		return &entity.Link{
+			ID:          1,
+			ShortCode:   "old123",
+			OriginalURL: "https://example.com/typo",
+			UserID:      &userID,
+			IsActive:    true,
+			ExpiresAt:   &expiresAt,
+		}
+	}
+
+	t.Run("Success - Change URL and short code, clear expiration", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		newURL := "https://example.com/fixed"
+		newCode := "new123"
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(newLink(), nil)
+		mockLinkRepo.On("ExistsByShortCode", ctx, newCode).Return(false, nil)
+		mockLinkRepo.On("Update", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
+
+		link, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{
+			OriginalURL:    &newURL,
+			ShortCode:      &newCode,
+			ClearExpiresAt: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, newURL, link.OriginalURL)
+		assert.Equal(t, newCode, link.ShortCode)
+		assert.Nil(t, link.ExpiresAt)
+		assert.True(t, link.IsActive)
+		mockLinkRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Absent fields stay unchanged", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		original := newLink()
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(original, nil)
+		mockLinkRepo.On("Update", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
+
+		inactive := false
+		link, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{IsActive: &inactive})
+
+		assert.NoError(t, err)
+		assert.False(t, link.IsActive)
+		assert.Equal(t, "old123", link.ShortCode)
+		assert.NotNil(t, link.ExpiresAt)
+	})
+
+	t.Run("Error - Short code already taken", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		taken := "taken1"
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(newLink(), nil)
+		mockLinkRepo.On("ExistsByShortCode", ctx, taken).Return(true, nil)
+
+		link, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{ShortCode: &taken})
+
+		assert.ErrorIs(t, err, ErrShortCodeExists)
+		assert.Nil(t, link)
+		mockLinkRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Owner cannot re-enable a link disabled by an administrator", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		disabled := newLink()
+		disabled.IsActive = false
+		disabled.DisabledByAdmin = true
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(disabled, nil)
+
+		active := true
+		_, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{IsActive: &active})
+
+		assert.ErrorIs(t, err, ErrLinkDisabled)
+		mockLinkRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Invalid URL", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		invalid := "not a url"
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(newLink(), nil)
+
+		_, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{OriginalURL: &invalid})
+
+		assert.ErrorIs(t, err, ErrInvalidURL)
+	})
+
+	t.Run("Error - New start is after the current expiration", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(newLink(), nil)
+
+		startsAt := time.Now().Add(48 * time.Hour)
+		_, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{StartsAt: &startsAt})
+
+		assert.ErrorIs(t, err, ErrInvalidWindow)
+		mockLinkRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Fallback URL is set and cleared", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		current := newLink()
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(current, nil)
+		mockLinkRepo.On("Update", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
+
+		fallbackURL := "https://example.com/sale-ended"
+		link, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{FallbackURL: &fallbackURL})
+		assert.NoError(t, err)
+		assert.Equal(t, fallbackURL, link.FallbackURL)
+
+		empty := ""
+		link, err = uc.UpdateLink(ctx, 1, userID, LinkUpdate{FallbackURL: &empty})
+		assert.NoError(t, err)
+		assert.Empty(t, link.FallbackURL)
+	})
+
+	t.Run("Success - Empty password removes protection", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		protected := newLink()
+		protected.PasswordHash = "$2a$10$hash"
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(protected, nil)
+		mockLinkRepo.On("Update", ctx, mock.AnythingOfType("*entity.Link")).Return(nil)
+
+		empty := ""
+		link, err := uc.UpdateLink(ctx, 1, userID, LinkUpdate{Password: &empty})
+
+		assert.NoError(t, err)
+		assert.False(t, link.IsPasswordProtected())
+	})
+}
+
+func TestLinkUseCase_CreateLinks(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("Success - Atomic batch in one transaction", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("FindExistingShortCodes", ctx, []string{"promo1"}).Return(nil, nil).Once()
+		mockLinkRepo.On("FindExistingShortCodes", ctx, mock.AnythingOfType("[]string")).Return(nil, nil).Once()
+		mockLinkRepo.On("CreateBatch", ctx, mock.MatchedBy(func(links []*entity.Link) bool {
+			return len(links) == 2 && links[0].ShortCode == "promo1" && len(links[1].ShortCode) == 6
+		})).Return(nil)
+
+		results, err := uc.CreateLinks(ctx, &userID, []LinkInput{
+			{OriginalURL: "https://example.com/a", CustomCode: "promo1"},
+			{OriginalURL: "https://example.com/b"},
+		}, true)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		for _, r := range results {
+			assert.NoError(t, r.Err)
+			assert.NotNil(t, r.Link)
+		}
+		mockLinkRepo.AssertNotCalled(t, "ExistsByShortCode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Atomic batch rejected as a whole", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("FindExistingShortCodes", ctx, []string{"dup123"}).Return(nil, nil)
+		mockLinkRepo.On("FindExistingShortCodes", ctx, mock.AnythingOfType("[]string")).Return(nil, nil)
+
+		results, err := uc.CreateLinks(ctx, &userID, []LinkInput{
+			{OriginalURL: "https://example.com/a", CustomCode: "dup123"},
+			{OriginalURL: "https://example.com/b", CustomCode: "dup123"},
+			{OriginalURL: "not a url"},
+		}, true)
+
+		assert.NoError(t, err)
+		assert.Nil(t, results[0].Link)
+		assert.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[1].Err, ErrShortCodeExists)
+		assert.ErrorIs(t, results[2].Err, ErrInvalidURL)
+		mockLinkRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Partial batch skips taken codes", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("FindExistingShortCodes", ctx, []string{"taken1", "free12"}).Return([]string{"taken1"}, nil)
+		mockLinkRepo.On("Create", ctx, mock.AnythingOfType("*entity.Link")).Return(nil).Once()
+
+		results, err := uc.CreateLinks(ctx, &userID, []LinkInput{
+			{OriginalURL: "https://example.com/a", CustomCode: "taken1"},
+			{OriginalURL: "https://example.com/b", CustomCode: "free12"},
+		}, false)
+
+		assert.NoError(t, err)
+		assert.ErrorIs(t, results[0].Err, ErrShortCodeExists)
+		assert.Equal(t, "free12", results[1].Link.ShortCode)
+		mockLinkRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid tags reject the link", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("FindExistingShortCodes", ctx, []string(nil)).Return(nil, nil)
+
+		results, err := uc.CreateLinks(ctx, &userID, []LinkInput{
+			{OriginalURL: "https://example.com/a", Tags: []string{"promo", strings.Repeat("x", 51)}},
+		}, false)
+
+		assert.NoError(t, err)
+		assert.ErrorIs(t, results[0].Err, ErrInvalidTagName)
+		mockLinkRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLinkUseCase_ExportLinks(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("Success - Reads all pages", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		firstPage := make([]*entity.Link, exportPageSize)
+		for i := range firstPage {
+			firstPage[i] = &entity.Link{ID: int64(i + 1)}
+		}
+
+		mockLinkRepo.On("List", ctx, entity.LinkFilter{UserID: &userID, Limit: exportPageSize}).Return(firstPage, nil)
+		mockLinkRepo.On("List", ctx, entity.LinkFilter{UserID: &userID, Offset: exportPageSize, Limit: exportPageSize}).
+			Return([]*entity.Link{{ID: 1000}}, nil)
+
+		count := 0
+		err := uc.ExportLinks(ctx, userID, func(link *entity.Link) error {
+			count++
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, exportPageSize+1, count)
+		mockLinkRepo.AssertExpectations(t)
+	})
+}
+
+func TestLinkUseCase_ListClicks(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	owned := &entity.Link{ID: 1, UserID: &userID}
+
+	t.Run("Success - Returns cursor when more clicks exist", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		clicks := []*entity.LinkClick{
+			{ID: 3, LinkID: 1, IPAddress: "10.0.0.1", ClickedAt: now},
+			{ID: 2, LinkID: 1, IPAddress: "10.0.0.2", ClickedAt: now.Add(-time.Minute)},
+			{ID: 1, LinkID: 1, IPAddress: "10.0.0.3", ClickedAt: now.Add(-2 * time.Minute)},
+		}
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(owned, nil)
+		mockClickRepo.On("GetByLinkID", ctx, entity.ClickFilter{LinkID: 1, Limit: 3}).Return(clicks, nil)
+
+		page, err := uc.ListClicks(ctx, 1, userID, ClickQuery{Limit: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, page.Clicks, 2)
+		assert.NotEmpty(t, page.NextCursor)
+		assert.NotEqual(t, "10.0.0.1", page.Clicks[0].IPAddress)
+
+		cursor, err := decodeClickCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), cursor.ID)
+		assert.True(t, cursor.ClickedAt.Equal(now.Add(-time.Minute)))
+	})
+
+	t.Run("Success - Last page has no cursor", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
+
+		after := entity.ClickCursor{ClickedAt: time.UnixMicro(1700000000000000).UTC(), ID: 10}
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(owned, nil)
+		mockClickRepo.On("GetByLinkID", ctx, entity.ClickFilter{LinkID: 1, After: &after, Limit: 51}).
+			Return([]*entity.LinkClick{{ID: 9, IPAddress: "10.0.0.1"}}, nil)
+
+		page, err := uc.ListClicks(ctx, 1, userID, ClickQuery{Cursor: encodeClickCursor(after), Limit: 50})
+
+		assert.NoError(t, err)
+		assert.Len(t, page.Clicks, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Error - Malformed cursor", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		uc := NewLinkUseCase(mockLinkRepo, new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(owned, nil)
+
+		_, err := uc.ListClicks(ctx, 1, userID, ClickQuery{Cursor: "!!!", Limit: 50})
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestLinkUseCase_GetLinkStats(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	owned := &entity.Link{ID: 1, UserID: &userID}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success - Bots are excluded by default", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
+
+		stats := &entity.LinkStats{LinkID: 1, TotalClicks: 10, BotClicks: 4}
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(owned, nil)
+		mockClickRepo.On("GetStats", ctx, entity.StatsFilter{LinkID: 1, From: from, To: to, Location: time.UTC}).Return(stats, nil)
+
+		result, err := uc.GetLinkStats(ctx, 1, userID, StatsQuery{From: from, To: to})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), result.TotalClicks)
+		assert.Len(t, result.Timeline, 32)
+		mockClickRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Bots included on request", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(owned, nil)
+		mockClickRepo.On("GetStats", ctx, entity.StatsFilter{LinkID: 1, From: from, To: to, IncludeBots: true, Location: time.UTC}).
+			Return(&entity.LinkStats{LinkID: 1}, nil)
+
+		_, err := uc.GetLinkStats(ctx, 1, userID, StatsQuery{From: from, To: to, IncludeBots: true})
+
+		assert.NoError(t, err)
+		mockClickRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Timeline is gap-filled in the requested time zone", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
+
+		moscow, err := time.LoadLocation("Europe/Moscow")
+		assert.NoError(t, err)
+
+		// 22:00 UTC 1 января - это уже 2 января в Москве
+		weekFrom := time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC)
+		weekTo := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+		sparse := []entity.TimeBucket{
+			{Start: time.Date(2024, 1, 2, 0, 0, 0, 0, moscow), Clicks: 3},
+			{Start: time.Date(2024, 1, 4, 0, 0, 0, 0, moscow), Clicks: 5},
+		}
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(owned, nil)
+		mockClickRepo.On("GetStats", ctx, mock.Anything).Return(&entity.LinkStats{LinkID: 1, Timeline: sparse}, nil)
+
+		result, err := uc.GetLinkStats(ctx, 1, userID, StatsQuery{From: weekFrom, To: weekTo, Location: moscow})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/Moscow", result.Timezone)
+		assert.Equal(t, []int64{3, 0, 5, 0}, timelineClicks(result.Timeline))
+		assert.True(t, result.Timeline[0].Start.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, moscow)))
+	})
+
+	t.Run("Error - Unknown granularity", func(t *testing.T) {
+		uc := NewLinkUseCase(new(MockLinkRepository), new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		_, err := uc.GetLinkStats(ctx, 1, userID, StatsQuery{From: from, To: to, Granularity: "minute"})
+
+		assert.ErrorIs(t, err, ErrInvalidGranularity)
+	})
+
+	t.Run("Error - Too many buckets", func(t *testing.T) {
+		uc := NewLinkUseCase(new(MockLinkRepository), new(MockLinkClickRepository), nil, 6, "http://localhost:8080", "salt")
+
+		_, err := uc.GetLinkStats(ctx, 1, userID, StatsQuery{From: from.AddDate(-5, 0, 0), To: to, Granularity: entity.GranularityHour})
+
+		assert.ErrorIs(t, err, ErrTimelineTooLong)
+	})
+
+	t.Run("Error - Link of another user", func(t *testing.T) {
+		mockLinkRepo := new(MockLinkRepository)
+		mockClickRepo := new(MockLinkClickRepository)
+		uc := NewLinkUseCase(mockLinkRepo, mockClickRepo, nil, 6, "http://localhost:8080", "salt")
+
+		mockLinkRepo.On("GetByID", ctx, int64(1)).Return(owned, nil)
+
+		_, err := uc.GetLinkStats(ctx, 1, 2, StatsQuery{From: from, To: to})
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		mockClickRepo.AssertNotCalled(t, "GetStats", mock.Anything, mock.Anything)
+	})
+}
+
+func timelineClicks(timeline []entity.TimeBucket) []int64 {
+	clicks := make([]int64, len(timeline))
+	for i, bucket := range timeline {
+		clicks[i] = bucket.Clicks
+	}
+	return clicks
+}
+
+func TestFillTimeline(t *testing.T) {
+	t.Run("Success - Weeks start on Monday", func(t *testing.T) {
+		// 3 января 2024 - среда
+		timeline := fillTimeline(nil, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC), entity.GranularityWeek, time.UTC)
+
+		assert.Len(t, timeline, 3)
+		assert.Equal(t, time.Monday, timeline[0].Start.Weekday())
+		assert.Equal(t, 1, timeline[0].Start.Day())
+	})
+
+	t.Run("Success - Months have calendar boundaries", func(t *testing.T) {
+		timeline := fillTimeline(nil, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), entity.GranularityMonth, time.UTC)
+
+		assert.Len(t, timeline, 3)
+		assert.Equal(t, time.February, timeline[1].Start.Month())
+	})
+
+	t.Run("Success - Daylight saving change keeps day boundaries", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+
+		// В ночь на 31 марта 2024 в Берлине переводят часы вперед
+		timeline := fillTimeline(nil, time.Date(2024, 3, 30, 12, 0, 0, 0, berlin), time.Date(2024, 4, 1, 12, 0, 0, 0, berlin), entity.GranularityDay, berlin)
+
+		assert.Len(t, timeline, 3)
+		for _, bucket := range timeline {
+			assert.Equal(t, 0, bucket.Start.Hour())
+		}
+	})
+
+	t.Run("Success - Hours over a backward clock change", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+
+		// 27 октября 2024 час 02:00-03:00 по Берлину повторяется
+		from := time.Date(2024, 10, 26, 23, 0, 0, 0, time.UTC)
+		timeline := fillTimeline(nil, from, from.Add(4*time.Hour), entity.GranularityHour, berlin)
+
+		assert.Len(t, timeline, 5)
+	})
 }
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/repository"
+)
+
+var (
+	ErrTagNotFound    = errors.New("tag not found")
+	ErrTagExists      = errors.New("tag with this name already exists")
+	ErrInvalidTagName = errors.New("tag name must be 1 to 50 characters")
+	ErrTooManyTags    = errors.New("a link can have at most 20 tags")
+)
+
+const (
+	// maxTagNameLength совпадает с размером колонки tags.name
+	maxTagNameLength = 50
+
+	maxTagsPerLink = 20
+)
+
+// TagUseCase defines methods for tag business logic
+type TagUseCase interface {
+	List(ctx context.Context, userID int64) ([]*entity.Tag, error)
+	Create(ctx context.Context, userID int64, name string) (*entity.Tag, error)
+	Rename(ctx context.Context, userID, tagID int64, name string) (*entity.Tag, error)
+	Delete(ctx context.Context, userID, tagID int64) error
+	SetLinkTags(ctx context.Context, userID, linkID int64, names []string) (*entity.Link, error)
+}
+
+type tagUseCase struct {
+	tagRepo  repository.TagRepository
+	linkRepo repository.LinkRepository
+}
+
+// NewTagUseCase creates a new tag use case
+func NewTagUseCase(tagRepo repository.TagRepository, linkRepo repository.LinkRepository) TagUseCase {
+	return &tagUseCase{
+		tagRepo:  tagRepo,
+		linkRepo: linkRepo,
+	}
+}
+
+// List возвращает теги пользователя по алфавиту
+func (uc *tagUseCase) List(ctx context.Context, userID int64) ([]*entity.Tag, error) {
+	tags, err := uc.tagRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Create создает тег с уникальным для пользователя именем
+func (uc *tagUseCase) Create(ctx context.Context, userID int64, name string) (*entity.Tag, error) {
+	name, err := uc.checkName(ctx, userID, 0, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &entity.Tag{UserID: userID, Name: name}
+	if err := uc.tagRepo.Create(ctx, tag); err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// Rename переименовывает тег пользователя; ссылки сохраняют тег под новым именем
+func (uc *tagUseCase) Rename(ctx context.Context, userID, tagID int64, name string) (*entity.Tag, error) {
+	tag, err := uc.getOwned(ctx, userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err = uc.checkName(ctx, userID, tagID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag.Name = name
+	if err := uc.tagRepo.Update(ctx, tag); err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// Delete удаляет тег пользователя и снимает его со всех ссылок
+func (uc *tagUseCase) Delete(ctx context.Context, userID, tagID int64) error {
+	if _, err := uc.getOwned(ctx, userID, tagID); err != nil {
+		return err
+	}
+
+	if err := uc.tagRepo.Delete(ctx, tagID); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return nil
+}
+
+// SetLinkTags заменяет теги ссылки. Несуществующие теги создаются, повторы игнорируются
+func (uc *tagUseCase) SetLinkTags(ctx context.Context, userID, linkID int64, names []string) (*entity.Link, error) {
+	if _, err := getOwnedLink(ctx, uc.linkRepo, linkID, userID); err != nil {
+		return nil, err
+	}
+
+	unique, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.tagRepo.SetLinkTags(ctx, linkID, userID, unique); err != nil {
+		return nil, fmt.Errorf("failed to set link tags: %w", err)
+	}
+
+	link, err := uc.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+
+	return link, nil
+}
+
+// getOwned возвращает тег, если он принадлежит пользователю.
+// Чужой тег неотличим от несуществующего
+func (uc *tagUseCase) getOwned(ctx context.Context, userID, tagID int64) (*entity.Tag, error) {
+	tag, err := uc.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	if tag == nil || tag.UserID != userID {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// checkName нормализует имя тега и проверяет, что оно не занято другим тегом пользователя
+func (uc *tagUseCase) checkName(ctx context.Context, userID, tagID int64, name string) (string, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := uc.tagRepo.GetByName(ctx, userID, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to check tag name: %w", err)
+	}
+	if existing != nil && existing.ID != tagID {
+		return "", ErrTagExists
+	}
+
+	return name, nil
+}
+
+// normalizeTagNames нормализует имена тегов ссылки, убирает повторы и проверяет их количество
+func normalizeTagNames(names []string) ([]string, error) {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	if len(unique) > maxTagsPerLink {
+		return nil, ErrTooManyTags
+	}
+	return unique, nil
+}
+
+// normalizeTagName убирает пробелы по краям и проверяет длину имени
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", ErrInvalidTagName
+	}
+	return name, nil
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagRepository is a mock implementation of TagRepository
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(ctx context.Context, id int64) (*entity.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByName(ctx context.Context, userID int64, name string) (*entity.Tag, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) GetByUserID(ctx context.Context, userID int64) ([]*entity.Tag, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) SetLinkTags(ctx context.Context, linkID, userID int64, names []string) error {
+	args := m.Called(ctx, linkID, userID, names)
+	return args.Error(0)
+}
+
+func TestTagUseCase_SetLinkTags(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+
+	t.Run("Success - names are trimmed and deduplicated", func(t *testing.T) {
+		tagRepo := new(MockTagRepository)
+		linkRepo := new(MockLinkRepository)
+		uc := NewTagUseCase(tagRepo, linkRepo)
+
+		linkRepo.On("GetByID", ctx, int64(10)).Return(&entity.Link{ID: 10, UserID: &userID, Tags: []string{"promo", "spring"}}, nil)
+		tagRepo.On("SetLinkTags", ctx, int64(10), userID, []string{"spring", "promo"}).Return(nil)
+
+		link, err := uc.SetLinkTags(ctx, userID, 10, []string{" spring", "promo", "spring "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promo", "spring"}, link.Tags)
+		tagRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - link of another user", func(t *testing.T) {
+		tagRepo := new(MockTagRepository)
+		linkRepo := new(MockLinkRepository)
+		uc := NewTagUseCase(tagRepo, linkRepo)
+
+		otherID := int64(2)
+		linkRepo.On("GetByID", ctx, int64(10)).Return(&entity.Link{ID: 10, UserID: &otherID}, nil)
+
+		_, err := uc.SetLinkTags(ctx, userID, 10, []string{"promo"})
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		tagRepo.AssertNotCalled(t, "SetLinkTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - too many tags", func(t *testing.T) {
+		linkRepo := new(MockLinkRepository)
+		uc := NewTagUseCase(new(MockTagRepository), linkRepo)
+
+		linkRepo.On("GetByID", ctx, int64(10)).Return(&entity.Link{ID: 10, UserID: &userID}, nil)
+		names := make([]string, maxTagsPerLink+1)
+		for i := range names {
+			names[i] = fmt.Sprintf("tag%d", i)
+		}
+
+		_, err := uc.SetLinkTags(ctx, userID, 10, names)
+
+		assert.ErrorIs(t, err, ErrTooManyTags)
+	})
+
+	t.Run("Error - empty tag name", func(t *testing.T) {
+		linkRepo := new(MockLinkRepository)
+		uc := NewTagUseCase(new(MockTagRepository), linkRepo)
+
+		linkRepo.On("GetByID", ctx, int64(10)).Return(&entity.Link{ID: 10, UserID: &userID}, nil)
+
+		_, err := uc.SetLinkTags(ctx, userID, 10, []string{"promo", " "})
+
+		assert.ErrorIs(t, err, ErrInvalidTagName)
+	})
+}
+
+func TestTagUseCase_Rename(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error - tag of another user", func(t *testing.T) {
+		tagRepo := new(MockTagRepository)
+		uc := NewTagUseCase(tagRepo, new(MockLinkRepository))
+
+		tagRepo.On("GetByID", ctx, int64(3)).Return(&entity.Tag{ID: 3, UserID: 2, Name: "promo"}, nil)
+
+		tag, err := uc.Rename(ctx, 1, 3, "sale")
+
+		assert.ErrorIs(t, err, ErrTagNotFound)
+		assert.Nil(t, tag)
+	})
+
+	t.Run("Success - keeping the same name", func(t *testing.T) {
+		tagRepo := new(MockTagRepository)
+		uc := NewTagUseCase(tagRepo, new(MockLinkRepository))
+
+		existing := &entity.Tag{ID: 3, UserID: 1, Name: "promo"}
+		tagRepo.On("GetByID", ctx, int64(3)).Return(existing, nil)
+		tagRepo.On("GetByName", ctx, int64(1), "promo").Return(existing, nil)
+		tagRepo.On("Update", ctx, existing).Return(nil)
+
+		tag, err := uc.Rename(ctx, 1, 3, "promo")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "promo", tag.Name)
+	})
+}
@@ -13,35 +13,55 @@ import (
 )
 
 var (
-	ErrUserNotFound       = errors.New("пользователь не найден")
-	ErrUserExists         = errors.New("пользователь с такой почтой уже существует")
-	ErrInvalidEmail       = errors.New("некорректный формат электронной почты")
-	ErrInvalidPassword    = errors.New("некорректный формат пароля")
-	ErrInvalidCredentials = errors.New("неверные учетные данные")
+	ErrUserNotFound        = errors.New("пользователь не найден")
+	ErrUserExists          = errors.New("пользователь с такой почтой уже существует")
+	ErrInvalidEmail        = errors.New("некорректный формат электронной почты")
+	ErrInvalidUserFallback = errors.New("резервный адрес должен быть абсолютным http(s) URL")
+	ErrInvalidPassword     = errors.New("некорректный формат пароля")
+	ErrInvalidCredentials  = errors.New("неверные учетные данные")
+	ErrInvalidRefreshToken = errors.New("недействительный refresh-токен")
+	ErrSessionRevoked      = errors.New("сессия отозвана")
 )
 
+// refreshTokenBytes is the amount of entropy in a refresh token
+const refreshTokenBytes = 32
+
+// TokenPair holds a short-lived access token and a rotating refresh token
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
 // UserUseCase defines methods for user business logic
 type UserUseCase interface {
 	Register(ctx context.Context, email, password string) (*entity.User, error)
-	Login(ctx context.Context, email, password string) (*entity.User, string, error)
+	Login(ctx context.Context, email, password string) (*entity.User, *TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, refreshToken string, allSessions bool) error
+	Authenticate(ctx context.Context, claims *utils.Claims) (*entity.User, error)
 	GetByID(ctx context.Context, userID int64) (*entity.User, error)
-	Update(ctx context.Context, userID int64, email string) error
+	Update(ctx context.Context, userID int64, email string, fallbackURL *string) error
 	ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error
 	GetStats(ctx context.Context, userID int64) (*entity.UserStats, error)
 }
 
 type userUseCase struct {
-	userRepo  repository.UserRepository
-	jwtSecret string
-	jwtExpire int
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	jwtSecret        string
+	accessTTL        time.Duration
+	refreshTTL       time.Duration
 }
 
 // NewUserUseCase creates a new user use case
-func NewUserUseCase(userRepo repository.UserRepository, jwtSecret string, jwtExpire int) UserUseCase {
+func NewUserUseCase(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, jwtSecret string, accessTTL, refreshTTL time.Duration) UserUseCase {
 	return &userUseCase{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtExpire: jwtExpire,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		jwtSecret:        jwtSecret,
+		accessTTL:        accessTTL,
+		refreshTTL:       refreshTTL,
 	}
 }
 
@@ -72,6 +92,7 @@ func (uc *userUseCase) Register(ctx context.Context, email, password string) (*e
 	user := &entity.User{
 		Email:        email,
 		PasswordHash: hashedPassword,
+		Role:         entity.RoleUser,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -83,26 +104,141 @@ func (uc *userUseCase) Register(ctx context.Context, email, password string) (*e
 	return user, nil
 }
 
-// Login выполняет аутентификацию пользователя и возвращает JWT токен
-func (uc *userUseCase) Login(ctx context.Context, email, password string) (*entity.User, string, error) {
+// Login выполняет аутентификацию пользователя и возвращает пару токенов
+func (uc *userUseCase) Login(ctx context.Context, email, password string) (*entity.User, *TokenPair, error) {
 	user, err := uc.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка получения пользователя: %w", err)
+		return nil, nil, fmt.Errorf("ошибка получения пользователя: %w", err)
 	}
 	if user == nil {
-		return nil, "", ErrInvalidCredentials
+		return nil, nil, ErrInvalidCredentials
 	}
 
 	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return nil, "", ErrInvalidCredentials
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	tokens, err := uc.issueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов. Старый токен
+// отзывается; повторное предъявление отозванного токена считается кражей
+// и завершает все сессии пользователя
+func (uc *userUseCase) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	stored, err := uc.refreshTokenRepo.GetByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения refresh-токена: %w", err)
+	}
+	if stored == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		if err := uc.revokeAllSessions(ctx, stored.UserID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	revoked, err := uc.refreshTokenRepo.Revoke(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка отзыва refresh-токена: %w", err)
+	}
+	if !revoked {
+		// Токен уже обменян параллельным запросом
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователя: %w", err)
+	}
+	if user == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return uc.issueTokens(ctx, user)
+}
+
+// Logout отзывает refresh-токен; при allSessions завершает все сессии пользователя
+func (uc *userUseCase) Logout(ctx context.Context, refreshToken string, allSessions bool) error {
+	stored, err := uc.refreshTokenRepo.GetByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return fmt.Errorf("ошибка получения refresh-токена: %w", err)
+	}
+	if stored == nil {
+		return ErrInvalidRefreshToken
+	}
+
+	if allSessions {
+		return uc.revokeAllSessions(ctx, stored.UserID)
+	}
+
+	if _, err := uc.refreshTokenRepo.Revoke(ctx, stored.ID); err != nil {
+		return fmt.Errorf("ошибка отзыва refresh-токена: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate проверяет, что access-токен не отозван, и возвращает актуального пользователя
+func (uc *userUseCase) Authenticate(ctx context.Context, claims *utils.Claims) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователя: %w", err)
+	}
+	if user == nil || user.TokenVersion != claims.TokenVersion {
+		return nil, ErrSessionRevoked
 	}
+	return user, nil
+}
 
-	token, err := utils.GenerateJWT(user.ID, user.Email, "user", uc.jwtSecret, time.Duration(uc.jwtExpire)*time.Hour)
+// issueTokens выпускает access-токен и сохраняет новый refresh-токен
+func (uc *userUseCase) issueTokens(ctx context.Context, user *entity.User) (*TokenPair, error) {
+	accessToken, err := utils.GenerateJWT(user.ID, user.Email, string(user.Role), user.TokenVersion, uc.jwtSecret, uc.accessTTL)
 	if err != nil {
-		return nil, "", fmt.Errorf("ошибка генерации токена: %w", err)
+		return nil, fmt.Errorf("ошибка генерации токена: %w", err)
 	}
 
-	return user, token, nil
+	refreshToken, err := utils.GenerateSecureToken(refreshTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка генерации refresh-токена: %w", err)
+	}
+
+	err = uc.refreshTokenRepo.Create(ctx, &entity.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(uc.refreshTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения refresh-токена: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    uc.accessTTL,
+	}, nil
+}
+
+// revokeAllSessions отзывает все refresh-токены и делает недействительными выданные access-токены
+func (uc *userUseCase) revokeAllSessions(ctx context.Context, userID int64) error {
+	if err := uc.refreshTokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("ошибка отзыва refresh-токенов: %w", err)
+	}
+	if err := uc.userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return fmt.Errorf("ошибка отзыва access-токенов: %w", err)
+	}
+	return nil
 }
 
 // GetByID получает пользователя по ID
@@ -117,11 +253,15 @@ func (uc *userUseCase) GetByID(ctx context.Context, userID int64) (*entity.User,
 	return user, nil
 }
 
-// Update обновляет информацию о пользователе
-func (uc *userUseCase) Update(ctx context.Context, userID int64, email string) error {
+// Update обновляет информацию о пользователе. fallbackURL == nil оставляет резервный адрес
+// без изменений, пустая строка убирает его
+func (uc *userUseCase) Update(ctx context.Context, userID int64, email string, fallbackURL *string) error {
 	if email != "" && !validator.IsValidEmail(email) {
 		return ErrInvalidEmail
 	}
+	if fallbackURL != nil && *fallbackURL != "" && !isValidFallbackURL(*fallbackURL) {
+		return ErrInvalidUserFallback
+	}
 
 	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -150,6 +290,10 @@ func (uc *userUseCase) Update(ctx context.Context, userID int64, email string) e
 		user.Email = email
 	}
 
+	if fallbackURL != nil {
+		user.FallbackURL = *fallbackURL
+	}
+
 	user.UpdatedAt = time.Now()
 
 	if err := uc.userRepo.Update(ctx, user); err != nil {
@@ -159,7 +303,7 @@ func (uc *userUseCase) Update(ctx context.Context, userID int64, email string) e
 	return nil
 }
 
-// ChangePassword изменяет пароль пользователя
+// ChangePassword изменяет пароль пользователя и завершает все его сессии
 func (uc *userUseCase) ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error {
 	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -189,7 +333,8 @@ func (uc *userUseCase) ChangePassword(ctx context.Context, userID int64, oldPass
 		return fmt.Errorf("ошибка обновления пользователя: %w", err)
 	}
 
-	return nil
+	// После смены пароля все существующие сессии завершаются
+	return uc.revokeAllSessions(ctx, userID)
 }
 
 // GetStats получает статистику пользователя
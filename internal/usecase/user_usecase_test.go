@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+	"github.com/raison-collab/LinkShorternetBackend/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRefreshTokenRepository is a mock implementation of RefreshTokenRepository
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func newTestUserUseCase(userRepo *MockUserRepository, tokenRepo *MockRefreshTokenRepository) UserUseCase {
+	return NewUserUseCase(userRepo, tokenRepo, "test-secret", 15*time.Minute, 24*time.Hour)
+}
+
+func TestUserUseCase_Login(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - issues access and refresh tokens", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockTokenRepo := new(MockRefreshTokenRepository)
+		uc := newTestUserUseCase(mockUserRepo, mockTokenRepo)
+
+		hash, _ := utils.HashPassword("password123")
+		user := &entity.User{ID: 1, Email: "user@example.com", PasswordHash: hash, Role: entity.RoleUser, TokenVersion: 3}
+
+		mockUserRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+		mockTokenRepo.On("Create", ctx, mock.MatchedBy(func(token *entity.RefreshToken) bool {
+			return token.UserID == 1 && len(token.TokenHash) == 64
+		})).Return(nil)
+
+		_, tokens, err := uc.Login(ctx, user.Email, "password123")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.Equal(t, 15*time.Minute, tokens.ExpiresIn)
+
+		claims, err := utils.ValidateJWT(tokens.AccessToken, "test-secret")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, claims.TokenVersion)
+		mockTokenRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUseCase_Refresh(t *testing.T) {
+	ctx := context.Background()
+	user := &entity.User{ID: 1, Email: "user@example.com", Role: entity.RoleUser}
+
+	t.Run("Success - rotates refresh token", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockTokenRepo := new(MockRefreshTokenRepository)
+		uc := newTestUserUseCase(mockUserRepo, mockTokenRepo)
+
+		stored := &entity.RefreshToken{ID: 10, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockTokenRepo.On("GetByHash", ctx, utils.HashToken("old-token")).Return(stored, nil)
+		mockTokenRepo.On("Revoke", ctx, int64(10)).Return(true, nil)
+		mockUserRepo.On("GetByID", ctx, int64(1)).Return(user, nil)
+		mockTokenRepo.On("Create", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
+
+		tokens, err := uc.Refresh(ctx, "old-token")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "old-token", tokens.RefreshToken)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - reuse of revoked token revokes all sessions", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockTokenRepo := new(MockRefreshTokenRepository)
+		uc := newTestUserUseCase(mockUserRepo, mockTokenRepo)
+
+		revokedAt := time.Now().Add(-time.Minute)
+		stored := &entity.RefreshToken{ID: 10, UserID: 1, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+
+		mockTokenRepo.On("GetByHash", ctx, utils.HashToken("stolen")).Return(stored, nil)
+		mockTokenRepo.On("RevokeAllByUserID", ctx, int64(1)).Return(nil)
+		mockUserRepo.On("IncrementTokenVersion", ctx, int64(1)).Return(nil)
+
+		tokens, err := uc.Refresh(ctx, "stolen")
+
+		assert.Nil(t, tokens)
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+		mockTokenRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - expired token", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockTokenRepo := new(MockRefreshTokenRepository)
+		uc := newTestUserUseCase(mockUserRepo, mockTokenRepo)
+
+		stored := &entity.RefreshToken{ID: 10, UserID: 1, ExpiresAt: time.Now().Add(-time.Hour)}
+		mockTokenRepo.On("GetByHash", ctx, utils.HashToken("expired")).Return(stored, nil)
+
+		_, err := uc.Refresh(ctx, "expired")
+
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+		mockTokenRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - unknown token", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockTokenRepo := new(MockRefreshTokenRepository)
+		uc := newTestUserUseCase(mockUserRepo, mockTokenRepo)
+
+		mockTokenRepo.On("GetByHash", ctx, utils.HashToken("unknown")).Return(nil, nil)
+
+		_, err := uc.Refresh(ctx, "unknown")
+
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+	})
+}
+
+func TestUserUseCase_Authenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - current token version", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := newTestUserUseCase(mockUserRepo, new(MockRefreshTokenRepository))
+
+		mockUserRepo.On("GetByID", ctx, int64(1)).Return(&entity.User{ID: 1, Role: entity.RoleAdmin, TokenVersion: 2}, nil)
+
+		user, err := uc.Authenticate(ctx, &utils.Claims{UserID: 1, TokenVersion: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleAdmin, user.Role)
+	})
+
+	t.Run("Error - outdated token version", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := newTestUserUseCase(mockUserRepo, new(MockRefreshTokenRepository))
+
+		mockUserRepo.On("GetByID", ctx, int64(1)).Return(&entity.User{ID: 1, TokenVersion: 3}, nil)
+
+		_, err := uc.Authenticate(ctx, &utils.Claims{UserID: 1, TokenVersion: 2})
+
+		assert.Equal(t, ErrSessionRevoked, err)
+	})
+}
+
+func TestUserUseCase_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - revokes all sessions", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		mockTokenRepo := new(MockRefreshTokenRepository)
+		uc := newTestUserUseCase(mockUserRepo, mockTokenRepo)
+
+		hash, _ := utils.HashPassword("old-password")
+		user := &entity.User{ID: 1, PasswordHash: hash}
+
+		mockUserRepo.On("GetByID", ctx, int64(1)).Return(user, nil)
+		mockUserRepo.On("Update", ctx, user).Return(nil)
+		mockTokenRepo.On("RevokeAllByUserID", ctx, int64(1)).Return(nil)
+		mockUserRepo.On("IncrementTokenVersion", ctx, int64(1)).Return(nil)
+
+		err := uc.ChangePassword(ctx, 1, "old-password", "NewPassword123")
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUseCase_Update(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - Sets account fallback and keeps email", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := newTestUserUseCase(mockUserRepo, new(MockRefreshTokenRepository))
+
+		user := &entity.User{ID: 1, Email: "user@example.com"}
+		mockUserRepo.On("GetByID", ctx, int64(1)).Return(user, nil)
+		mockUserRepo.On("Update", ctx, user).Return(nil)
+
+		fallbackURL := "https://example.com/gone"
+		err := uc.Update(ctx, 1, "", &fallbackURL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fallbackURL, user.FallbackURL)
+		assert.Equal(t, "user@example.com", user.Email)
+	})
+
+	t.Run("Error - Fallback without scheme", func(t *testing.T) {
+		mockUserRepo := new(MockUserRepository)
+		uc := newTestUserUseCase(mockUserRepo, new(MockRefreshTokenRepository))
+
+		fallbackURL := "example.com/gone"
+		err := uc.Update(ctx, 1, "", &fallbackURL)
+
+		assert.ErrorIs(t, err, ErrInvalidUserFallback)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
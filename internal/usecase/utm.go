@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/raison-collab/LinkShorternetBackend/internal/domain/entity"
+)
+
+// maxUTMLength совпадает с размером колонок utm_* в таблице links
+const maxUTMLength = 255
+
+// utmKeys - имена параметров UTM в порядке, в котором они дописываются к адресу
+var utmKeys = [...]string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content"}
+
+func utmValues(utm entity.UTM) [len(utmKeys)]string {
+	return [...]string{utm.Source, utm.Medium, utm.Campaign, utm.Term, utm.Content}
+}
+
+// applyUTM дописывает к адресу заданные параметры UTM. Параметры с тем же именем,
+// уже присутствующие в адресе, заменяются; остальная строка запроса и фрагмент не меняются
+func applyUTM(rawURL string, utm entity.UTM) string {
+	if utm.IsZero() {
+		return rawURL
+	}
+
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	base, query, _ := strings.Cut(base, "?")
+
+	values := utmValues(utm)
+	replaced := make(map[string]bool, len(utmKeys))
+	for i, key := range utmKeys {
+		if values[i] != "" {
+			replaced[key] = true
+		}
+	}
+
+	var params []string
+	if query != "" {
+		for _, param := range strings.Split(query, "&") {
+			key, _, _ := strings.Cut(param, "=")
+			if name, err := url.QueryUnescape(key); err == nil && replaced[name] {
+				continue
+			}
+			params = append(params, param)
+		}
+	}
+	for i, key := range utmKeys {
+		if values[i] != "" {
+			params = append(params, key+"="+url.QueryEscape(values[i]))
+		}
+	}
+
+	result := base + "?" + strings.Join(params, "&")
+	if hasFragment {
+		result += "#" + fragment
+	}
+	return result
+}
+
+// utmFromURL читает параметры UTM из строки запроса адреса
+func utmFromURL(rawURL string) entity.UTM {
+	base, _, _ := strings.Cut(rawURL, "#")
+	_, query, _ := strings.Cut(base, "?")
+
+	// Некорректные пары пропускаются, остальные значения ParseQuery все равно возвращает
+	values, _ := url.ParseQuery(query)
+
+	return entity.UTM{
+		Source:   strings.TrimSpace(values.Get("utm_source")),
+		Medium:   strings.TrimSpace(values.Get("utm_medium")),
+		Campaign: strings.TrimSpace(values.Get("utm_campaign")),
+		Term:     strings.TrimSpace(values.Get("utm_term")),
+		Content:  strings.TrimSpace(values.Get("utm_content")),
+	}
+}
+
+// validateUTM проверяет, что параметры UTM помещаются в колонки базы
+func validateUTM(utm entity.UTM) error {
+	for _, value := range utmValues(utm) {
+		if utf8.RuneCountInString(value) > maxUTMLength {
+			return ErrInvalidUTM
+		}
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+-- Allow pausing links without deleting them
+ALTER TABLE links ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_links_user_id_is_active ON links(user_id, is_active);
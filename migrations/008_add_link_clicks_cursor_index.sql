@@ -0,0 +1,5 @@
+-- Keyset pagination of the click log: WHERE link_id = ? AND (clicked_at, id) < (?, ?)
+CREATE INDEX IF NOT EXISTS idx_link_clicks_link_id_clicked_at_id ON link_clicks(link_id, clicked_at DESC, id DESC);
+
+-- Superseded by the composite index above
+DROP INDEX IF EXISTS idx_link_clicks_link_id;
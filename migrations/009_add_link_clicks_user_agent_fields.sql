@@ -0,0 +1,15 @@
+-- User-Agent classification parsed at ingestion time
+ALTER TABLE link_clicks ADD COLUMN IF NOT EXISTS device_type VARCHAR(20);
+ALTER TABLE link_clicks ADD COLUMN IF NOT EXISTS os VARCHAR(50);
+ALTER TABLE link_clicks ADD COLUMN IF NOT EXISTS browser VARCHAR(50);
+ALTER TABLE link_clicks ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Existing rows keep the device classification they were reported with before;
+-- OS and browser stay NULL and are reported as Unknown
+UPDATE link_clicks
+SET device_type = CASE
+        WHEN user_agent LIKE '%Mobile%' THEN 'Mobile'
+        WHEN user_agent LIKE '%Tablet%' THEN 'Tablet'
+        ELSE 'Desktop'
+    END
+WHERE device_type IS NULL;
@@ -0,0 +1,37 @@
+-- Pre-aggregated click counts per link and dimensions. Empty strings stand for unknown values,
+-- so the dimensions can be part of the primary key
+CREATE TABLE IF NOT EXISTS link_click_rollups_hourly (
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the UTC hour
+    country VARCHAR(100) NOT NULL DEFAULT '',
+    device_type VARCHAR(20) NOT NULL DEFAULT '',
+    os VARCHAR(50) NOT NULL DEFAULT '',
+    browser VARCHAR(50) NOT NULL DEFAULT '',
+    referer_host VARCHAR(255) NOT NULL DEFAULT '',
+    is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    clicks BIGINT NOT NULL,
+    PRIMARY KEY (link_id, bucket, country, device_type, os, browser, referer_host, is_bot)
+);
+
+CREATE TABLE IF NOT EXISTS link_click_rollups_daily (
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the UTC day
+    country VARCHAR(100) NOT NULL DEFAULT '',
+    device_type VARCHAR(20) NOT NULL DEFAULT '',
+    os VARCHAR(50) NOT NULL DEFAULT '',
+    browser VARCHAR(50) NOT NULL DEFAULT '',
+    referer_host VARCHAR(255) NOT NULL DEFAULT '',
+    is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    clicks BIGINT NOT NULL,
+    PRIMARY KEY (link_id, bucket, country, device_type, os, browser, referer_host, is_bot)
+);
+
+-- The aggregator rebuilds whole hours by time range
+CREATE INDEX IF NOT EXISTS idx_link_click_rollups_hourly_bucket ON link_click_rollups_hourly(bucket);
+CREATE INDEX IF NOT EXISTS idx_link_click_rollups_daily_bucket ON link_click_rollups_daily(bucket);
+
+-- Single row: clicks before rolled_up_to are fully aggregated
+CREATE TABLE IF NOT EXISTS click_rollup_state (
+    id SMALLINT PRIMARY KEY CHECK (id = 1),
+    rolled_up_to TIMESTAMP WITH TIME ZONE NOT NULL
+);
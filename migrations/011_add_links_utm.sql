@@ -0,0 +1,50 @@
+-- UTM parameters of the destination URL, kept in sync with original_url
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_source VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_medium VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_campaign VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_term VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_content VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Reads a query parameter of a URL like url.ParseQuery: the first occurrence wins,
+-- "+" and percent escapes are decoded. Malformed values are treated as absent.
+-- pg_temp keeps the helper out of the schema: it is dropped with the session
+CREATE OR REPLACE FUNCTION pg_temp.url_query_param(url TEXT, name TEXT) RETURNS TEXT AS $$
+DECLARE
+    value TEXT;
+BEGIN
+    SELECT substr(param, length(name) + 2) INTO value
+    FROM regexp_split_to_table(substring(split_part(url, '#', 1) FROM '\?(.*)$'), '&') WITH ORDINALITY AS p(param, n)
+    WHERE split_part(param, '=', 1) = name
+    ORDER BY n
+    LIMIT 1;
+
+    IF value IS NULL OR value ~ '%(?![0-9A-Fa-f]{2})' THEN
+        RETURN '';
+    END IF;
+
+    BEGIN
+        SELECT convert_from(string_agg(
+                CASE WHEN part[1] LIKE '\%%' THEN decode(substr(part[1], 2), 'hex') ELSE convert_to(part[1], 'UTF8') END,
+                ''::BYTEA ORDER BY n), 'UTF8')
+        INTO value
+        FROM regexp_matches(replace(value, '+', ' '), '(%[0-9A-Fa-f]{2}|[^%]+)', 'g') WITH ORDINALITY AS m(part, n);
+    EXCEPTION WHEN character_not_in_repertoire OR untranslatable_character THEN
+        RETURN '';
+    END;
+
+    RETURN left(btrim(COALESCE(value, '')), 255);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Backfill links created before the UTM columns existed
+UPDATE links
+SET utm_source = pg_temp.url_query_param(original_url, 'utm_source'),
+    utm_medium = pg_temp.url_query_param(original_url, 'utm_medium'),
+    utm_campaign = pg_temp.url_query_param(original_url, 'utm_campaign'),
+    utm_term = pg_temp.url_query_param(original_url, 'utm_term'),
+    utm_content = pg_temp.url_query_param(original_url, 'utm_content')
+WHERE original_url LIKE '%utm\_%'
+    AND utm_source = '' AND utm_medium = '' AND utm_campaign = '' AND utm_term = '' AND utm_content = '';
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_links_user_id_utm_campaign ON links(user_id, utm_campaign) WHERE utm_campaign <> '';
@@ -0,0 +1,36 @@
+-- Create folders table; a link belongs to at most one folder
+CREATE TABLE IF NOT EXISTS folders (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- Deleting a folder keeps its links
+ALTER TABLE links ADD COLUMN IF NOT EXISTS folder_id BIGINT REFERENCES folders(id) ON DELETE SET NULL;
+
+-- Create tags table
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- Create link_tags table
+CREATE TABLE IF NOT EXISTS link_tags (
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (link_id, tag_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_links_folder_id ON links(folder_id);
+CREATE INDEX IF NOT EXISTS idx_link_tags_tag_id ON link_tags(tag_id);
+
+-- Create updated_at trigger
+CREATE TRIGGER update_folders_updated_at BEFORE UPDATE
+    ON folders FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,11 @@
+-- Optional human-readable title of a link
+ALTER TABLE links ADD COLUMN IF NOT EXISTS title VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Full-text search over title, short code and destination
+ALTER TABLE links ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', title || ' ' || short_code || ' ' || original_url)) STORED;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_links_search_vector ON links USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_links_user_id_clicks ON links(user_id, clicks);
+CREATE INDEX IF NOT EXISTS idx_links_user_id_updated_at ON links(user_id, updated_at);
@@ -0,0 +1,2 @@
+-- Optional bcrypt hash of the password required to follow a link
+ALTER TABLE links ADD COLUMN IF NOT EXISTS password_hash VARCHAR(255);
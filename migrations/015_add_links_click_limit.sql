@@ -0,0 +1,9 @@
+-- Optional limit on redirects; NULL means unlimited
+ALTER TABLE links ADD COLUMN IF NOT EXISTS max_clicks INTEGER CHECK (max_clicks > 0);
+
+-- Redirects consumed against max_clicks. Unlike clicks it is incremented synchronously
+-- on redirect, so the limit cannot be overshot by concurrent requests
+ALTER TABLE links ADD COLUMN IF NOT EXISTS uses INTEGER NOT NULL DEFAULT 0;
+
+-- Delete the link once max_clicks is reached
+ALTER TABLE links ADD COLUMN IF NOT EXISTS delete_when_exhausted BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,10 @@
+-- Optional moment the link goes live; NULL means it is live immediately
+ALTER TABLE links ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'links_activation_window_check') THEN
+        ALTER TABLE links ADD CONSTRAINT links_activation_window_check
+            CHECK (starts_at IS NULL OR expires_at IS NULL OR starts_at < expires_at);
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+-- Where to send visitors of a link that is expired, inactive or exhausted
+ALTER TABLE links ADD COLUMN IF NOT EXISTS fallback_url TEXT NOT NULL DEFAULT '';
+
+-- Account-wide fallback for links without their own fallback_url
+ALTER TABLE users ADD COLUMN IF NOT EXISTS fallback_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,17 @@
+-- Expired links removed by the background sweeper in archive mode.
+-- The short code is no longer unique here: once archived it can be taken again
+CREATE TABLE IF NOT EXISTS links_archive (
+    id BIGINT PRIMARY KEY,
+    short_code VARCHAR(20) NOT NULL,
+    original_url TEXT NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_links_archive_user_id ON links_archive(user_id);
+CREATE INDEX IF NOT EXISTS idx_links_archive_short_code ON links_archive(short_code);
@@ -0,0 +1,5 @@
+-- Soft delete: deleted links stay in the trash with their clicks until the sweeper purges them.
+-- The short code stays taken while the link is in the trash, so it can be restored
+ALTER TABLE links ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_links_deleted_at ON links(deleted_at) WHERE deleted_at IS NOT NULL;
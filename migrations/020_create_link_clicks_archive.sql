@@ -0,0 +1,39 @@
+-- Click history of links archived by the sweeper: raw clicks and rollups are copied here
+-- before the link is deleted, so removing it from links does not lose its analytics
+CREATE TABLE IF NOT EXISTS link_clicks_archive (
+    id BIGINT PRIMARY KEY,
+    link_id BIGINT NOT NULL REFERENCES links_archive(id) ON DELETE CASCADE,
+    ip_address INET NOT NULL,
+    user_agent TEXT,
+    referer TEXT,
+    country VARCHAR(100),
+    city VARCHAR(100),
+    device_type VARCHAR(20),
+    os VARCHAR(50),
+    browser VARCHAR(50),
+    is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    clicked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS link_click_rollups_hourly_archive (
+    LIKE link_click_rollups_hourly INCLUDING DEFAULTS INCLUDING INDEXES
+);
+
+CREATE TABLE IF NOT EXISTS link_click_rollups_daily_archive (
+    LIKE link_click_rollups_daily INCLUDING DEFAULTS INCLUDING INDEXES
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_link_clicks_archive_link_id ON link_clicks_archive(link_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'link_click_rollups_hourly_archive_link_id_fkey') THEN
+        ALTER TABLE link_click_rollups_hourly_archive ADD CONSTRAINT link_click_rollups_hourly_archive_link_id_fkey
+            FOREIGN KEY (link_id) REFERENCES links_archive(id) ON DELETE CASCADE;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'link_click_rollups_daily_archive_link_id_fkey') THEN
+        ALTER TABLE link_click_rollups_daily_archive ADD CONSTRAINT link_click_rollups_daily_archive_link_id_fkey
+            FOREIGN KEY (link_id) REFERENCES links_archive(id) ON DELETE CASCADE;
+    END IF;
+END $$;
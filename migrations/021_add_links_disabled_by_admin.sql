@@ -0,0 +1,2 @@
+-- Moderation flag set by administrators; unlike is_active the owner cannot clear it
+ALTER TABLE links ADD COLUMN IF NOT EXISTS disabled_by_admin BOOLEAN NOT NULL DEFAULT FALSE;
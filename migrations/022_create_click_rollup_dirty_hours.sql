@@ -0,0 +1,23 @@
+-- Past hours that received clicks after they ended, e.g. clicks delivered late by the click queue.
+-- The aggregator rebuilds those below its watermark on its next run
+CREATE TABLE IF NOT EXISTS click_rollup_dirty_hours (
+    bucket TIMESTAMP WITH TIME ZONE PRIMARY KEY -- start of the UTC hour
+);
+
+-- The watermark is not read here: locking it would block every click insert while a rollup runs.
+-- Past hours are marked unconditionally; the aggregator drops marks of hours it has not reached yet
+CREATE OR REPLACE FUNCTION mark_click_rollup_dirty_hours()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO click_rollup_dirty_hours (bucket)
+    SELECT DISTINCT date_trunc('hour', clicked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+    FROM new_clicks
+    WHERE clicked_at < date_trunc('hour', now() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+    ON CONFLICT (bucket) DO NOTHING;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE TRIGGER mark_link_clicks_dirty_hours AFTER INSERT
+    ON link_clicks REFERENCING NEW TABLE AS new_clicks
+    FOR EACH STATEMENT EXECUTE FUNCTION mark_click_rollup_dirty_hours();
@@ -0,0 +1,166 @@
+// Package useragent classifies HTTP User-Agent strings into device type, OS and browser
+package useragent
+
+import "strings"
+
+// Device types
+const (
+	DeviceDesktop = "Desktop"
+	DeviceMobile  = "Mobile"
+	DeviceTablet  = "Tablet"
+	DeviceBot     = "Bot"
+	DeviceUnknown = "Unknown"
+)
+
+// Other is reported when the OS or browser is not recognized
+const Other = "Other"
+
+// Info is the result of parsing a User-Agent
+type Info struct {
+	DeviceType string
+	OS         string
+	Browser    string
+	IsBot      bool
+}
+
+// botSignatures are lowercase substrings of crawlers, link preview fetchers and HTTP libraries.
+// "bot" covers Googlebot, bingbot, Slackbot, Twitterbot, TelegramBot, LinkedInBot and most others
+var botSignatures = []string{
+	"bot", "crawl", "spider", "slurp", "archiver",
+	"facebookexternalhit", "facebookcatalog", "embedly", "vkshare", "whatsapp", "skypeuripreview",
+	"bitlypreview", "quora link preview", "outbrain", "pinterest", "redditbot", "discordbot",
+	"headlesschrome", "phantomjs", "lighthouse", "pingdom", "uptimerobot",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client", "java/",
+	"okhttp", "apache-httpclient", "libwww-perl", "axios/", "node-fetch",
+}
+
+type rule struct {
+	tokens []string
+	name   string
+}
+
+// osRules проверяются по порядку: Windows Phone и Android содержат токены Windows и Linux
+var osRules = []rule{
+	{[]string{"Windows Phone"}, "Windows Phone"},
+	{[]string{"Windows"}, "Windows"},
+	{[]string{"iPad"}, "iPadOS"},
+	{[]string{"iPhone", "iPod"}, "iOS"},
+	{[]string{"Android"}, "Android"},
+	{[]string{"CrOS"}, "Chrome OS"},
+	{[]string{"Macintosh", "Mac OS X"}, "macOS"},
+	{[]string{"Linux"}, "Linux"},
+}
+
+// browserRules проверяются по порядку: почти все браузеры на Chromium содержат Chrome/ и Safari/
+var browserRules = []rule{
+	{[]string{"Edg/", "Edge/", "EdgA/", "EdgiOS/"}, "Edge"},
+	{[]string{"OPR/", "Opera"}, "Opera"},
+	{[]string{"YaBrowser/"}, "Yandex Browser"},
+	{[]string{"SamsungBrowser/"}, "Samsung Internet"},
+	{[]string{"Firefox/", "FxiOS/"}, "Firefox"},
+	{[]string{"Chrome/", "CriOS/", "Chromium/"}, "Chrome"},
+	{[]string{"MSIE ", "Trident/"}, "Internet Explorer"},
+	{[]string{"Version/"}, "Safari"},
+}
+
+// BotDetector recognizes crawlers by User-Agent substrings.
+// It is safe for concurrent use
+type BotDetector struct {
+	signatures []string
+	ignore     []string
+}
+
+// NewBotDetector creates a detector using the built-in signatures plus extra ones.
+// User-Agents containing any of the ignore substrings are never treated as bots,
+// which allows counting e.g. in-app browsers that match a built-in signature.
+// Matching is case-insensitive
+func NewBotDetector(extra, ignore []string) *BotDetector {
+	return &BotDetector{
+		signatures: append(append([]string(nil), botSignatures...), lowerAll(extra)...),
+		ignore:     lowerAll(ignore),
+	}
+}
+
+var defaultDetector = NewBotDetector(nil, nil)
+
+// Parse classifies a User-Agent string with the built-in bot signatures
+func Parse(ua string) Info {
+	return defaultDetector.Parse(ua)
+}
+
+// IsBot reports whether the User-Agent matches a built-in bot signature
+func IsBot(ua string) bool {
+	return defaultDetector.IsBot(ua)
+}
+
+// Parse classifies a User-Agent string. It never fails: unknown values are reported as Other
+func (d *BotDetector) Parse(ua string) Info {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return Info{DeviceType: DeviceUnknown, OS: Other, Browser: Other}
+	}
+
+	info := Info{
+		OS:      match(ua, osRules),
+		Browser: match(ua, browserRules),
+		IsBot:   d.IsBot(ua),
+	}
+	info.DeviceType = deviceType(ua, info)
+
+	return info
+}
+
+// IsBot reports whether the User-Agent belongs to a crawler or non-browser client
+func (d *BotDetector) IsBot(ua string) bool {
+	lower := strings.ToLower(ua)
+	if containsAny(lower, d.ignore) {
+		return false
+	}
+	return containsAny(lower, d.signatures)
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func lowerAll(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func deviceType(ua string, info Info) string {
+	switch {
+	case info.IsBot:
+		return DeviceBot
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"),
+		strings.Contains(ua, "Kindle"), strings.Contains(ua, "Silk/"),
+		// Android-планшеты не добавляют Mobile в User-Agent
+		info.OS == "Android" && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), info.OS == "iOS", info.OS == "Windows Phone", info.OS == "Android":
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		for _, token := range r.tokens {
+			if strings.Contains(ua, token) {
+				return r.name
+			}
+		}
+	}
+	return Other
+}
@@ -0,0 +1,109 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want Info
+	}{
+		{
+			name: "Chrome on Windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want: Info{DeviceType: DeviceDesktop, OS: "Windows", Browser: "Chrome"},
+		},
+		{
+			name: "Edge on Windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.51",
+			want: Info{DeviceType: DeviceDesktop, OS: "Windows", Browser: "Edge"},
+		},
+		{
+			name: "Safari on macOS",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+			want: Info{DeviceType: DeviceDesktop, OS: "macOS", Browser: "Safari"},
+		},
+		{
+			name: "Safari on iPhone",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want: Info{DeviceType: DeviceMobile, OS: "iOS", Browser: "Safari"},
+		},
+		{
+			name: "Safari on iPad",
+			ua:   "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want: Info{DeviceType: DeviceTablet, OS: "iPadOS", Browser: "Safari"},
+		},
+		{
+			name: "Chrome on Android phone",
+			ua:   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+			want: Info{DeviceType: DeviceMobile, OS: "Android", Browser: "Chrome"},
+		},
+		{
+			name: "Android tablet",
+			ua:   "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want: Info{DeviceType: DeviceTablet, OS: "Android", Browser: "Chrome"},
+		},
+		{
+			name: "Firefox on Linux",
+			ua:   "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+			want: Info{DeviceType: DeviceDesktop, OS: "Linux", Browser: "Firefox"},
+		},
+		{
+			name: "Googlebot",
+			ua:   "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want: Info{DeviceType: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+		{
+			name: "Slack link preview",
+			ua:   "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+			want: Info{DeviceType: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+		{
+			name: "curl",
+			ua:   "curl/8.5.0",
+			want: Info{DeviceType: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+		{
+			name: "Empty",
+			ua:   "",
+			want: Info{DeviceType: DeviceUnknown, OS: Other, Browser: Other},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.ua))
+		})
+	}
+}
+
+func TestBotDetector(t *testing.T) {
+	whatsApp := "WhatsApp/2.24.8.78 A"
+	monitor := "AcmeStatusChecker/1.0"
+
+	t.Run("Success - Built-in signatures", func(t *testing.T) {
+		d := NewBotDetector(nil, nil)
+
+		assert.True(t, d.IsBot(whatsApp))
+		assert.True(t, d.IsBot("TelegramBot (like TwitterBot)"))
+		assert.False(t, d.IsBot(monitor))
+	})
+
+	t.Run("Success - Extra signatures are case-insensitive", func(t *testing.T) {
+		d := NewBotDetector([]string{" ACMESTATUSCHECKER "}, nil)
+
+		assert.True(t, d.IsBot(monitor))
+		assert.Equal(t, DeviceBot, d.Parse(monitor).DeviceType)
+	})
+
+	t.Run("Success - Ignore list overrides signatures", func(t *testing.T) {
+		d := NewBotDetector(nil, []string{"whatsapp"})
+
+		assert.False(t, d.IsBot(whatsApp))
+		assert.True(t, d.IsBot("curl/8.5.0"))
+	})
+}
@@ -11,14 +11,19 @@ import (
 type Claims struct {
 	UserID int64  `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
+	// TokenVersion must match the user's current version, otherwise the token is revoked
+	TokenVersion int `json:"ver"`
 	jwt.RegisteredClaims
 }
 
 // GenerateJWT generates a JWT token
-func GenerateJWT(userID int64, email, role, secret string, expiresIn time.Duration) (string, error) {
+func GenerateJWT(userID int64, email, role string, tokenVersion int, secret string, expiresIn time.Duration) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:       userID,
+		Email:        email,
+		Role:         role,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),